@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SergeyShpak/gosif/generator/types"
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// completionCommand is the builtin command that prints the shell completion
+// scripts.
+const completionCommand = "completion"
+
+// completionShells are the shells the completion scripts are generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionFunc describes a function for the shell completion scripts.
+type completionFunc struct {
+	Names []string
+	Flags []completionFlag
+	// Negations are the negated names of the boolean flags (e.g. no-verbose)
+	Negations []string
+}
+
+// completionFlag describes a flag for the shell completion scripts.
+type completionFlag struct {
+	Names     []string
+	ShortName *string
+	// TakesValue is unset for the boolean and the counter flags, whose value
+	// is optional
+	TakesValue bool
+	// Values are offered as the flag value if they are known
+	Values []string
+	// IsPath is set for the parameters that are completed with the file
+	// paths
+	IsPath bool
+	// Completer is the function that returns the candidate values, they are
+	// requested from the binary with the hidden __complete command
+	Completer string
+}
+
+// Patterns returns the flag as it can be passed on the command line, e.g.
+// --env|-e.
+func (f completionFlag) Patterns() string {
+	patterns := make([]string, 0, len(f.Names)+1)
+	for _, name := range f.Names {
+		patterns = append(patterns, "--"+name)
+	}
+	if f.ShortName != nil {
+		patterns = append(patterns, "-"+*f.ShortName)
+	}
+	return strings.Join(patterns, "|")
+}
+
+// getCompletionFunc collects the data of the function for the shell
+// completion scripts.
+func getCompletionFunc(fn *FuncForGenerator, completers map[string]string, opts *Options) completionFunc {
+	params := make([]*FuncParamData, 0, len(fn.RequiredParams)+len(fn.OptionalParams))
+	params = append(params, fn.RequiredParams...)
+	params = append(params, fn.OptionalParams...)
+	flags := make([]types.Flag, 0, len(params))
+	for _, p := range params {
+		flags = append(flags, *p.Flag)
+	}
+	aliases := flagAliases(flags, opts)
+	cf := completionFunc{
+		Names: opts.cliNames(fn.ParsedFunc.Name),
+		Flags: make([]completionFlag, 0, len(params)),
+	}
+	for _, p := range params {
+		isBool := p.RawParam.Type.Base.CoreType == "bool" && !p.RawParam.IsAnArray()
+		flag := completionFlag{
+			Names:      opts.cliNames(p.RawParam.Name),
+			TakesValue: !isBool && !p.IsCounter,
+			Values:     p.Flag.Choices,
+			IsPath:     isPathParam(p),
+			Completer:  completers[p.RawParam.Name],
+		}
+		if p.Flag.ShortName != nil && *p.Flag.ShortName != p.Flag.CLIName {
+			flag.ShortName = p.Flag.ShortName
+		}
+		cf.Flags = append(cf.Flags, flag)
+		if !isBool {
+			continue
+		}
+		for _, name := range flag.Names {
+			if _, ok := aliases["no-"+name]; !ok {
+				cf.Negations = append(cf.Negations, "no-"+name)
+			}
+		}
+	}
+	return cf
+}
+
+// isPathParam reports whether the parameter is a string that holds a file
+// path, judging by its name (e.g. path, outFile or workDir).
+func isPathParam(p *FuncParamData) bool {
+	if p.RawParam.Type.Base.CoreType != "string" {
+		return false
+	}
+	name := strings.ToLower(p.RawParam.Name)
+	for _, word := range []string{"path", "file", "dir"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Quoted returns the flag as it can be passed on the command line as a list
+// of Go strings, e.g. "--env", "-e".
+func (f completionFlag) Quoted() string {
+	patterns := strings.Split(f.Patterns(), "|")
+	for i, pattern := range patterns {
+		patterns[i] = fmt.Sprintf("%q", pattern)
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// hasCompleters reports whether any of the flags has a completion function.
+func hasCompleters(funcs []completionFunc) bool {
+	for _, fn := range funcs {
+		for _, f := range fn.Flags {
+			if len(f.Completer) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasCommand reports whether any of the functions is exposed under the
+// command name.
+func hasCommand(functions []*parser.PkgFunc, name string, opts *Options) bool {
+	for _, fn := range functions {
+		for _, cmd := range opts.cliNames(fn.Name) {
+			if cmd == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateCompletionFunction generates the function printing the shell
+// completion scripts.
+func generateCompletionFunction(funcs []completionFunc, builtins []string, hasConfig bool) (string, error) {
+	in := &tmplCompletionScriptInput{
+		Funcs:     funcs,
+		Shells:    completionShells,
+		HasConfig: hasConfig,
+	}
+	for _, builtin := range builtins {
+		if strings.HasPrefix(builtin, "--") {
+			in.BuiltinOptions = append(in.BuiltinOptions, strings.TrimPrefix(builtin, "--"))
+			continue
+		}
+		in.BuiltinCommands = append(in.BuiltinCommands, builtin)
+	}
+	bash, err := generateFromTemplate(tmplBashCompletion, in)
+	if err != nil {
+		return "", err
+	}
+	zsh, err := generateFromTemplate(tmplZshCompletion, in)
+	if err != nil {
+		return "", err
+	}
+	fish, err := generateFromTemplate(tmplFishCompletion, in)
+	if err != nil {
+		return "", err
+	}
+	out, err := generateFromTemplate(tmplCompletionFunction, &tmplCompletionFunctionInput{
+		Bash: bash,
+		Zsh:  zsh,
+		Fish: fish,
+	})
+	if err != nil || !hasCompleters(funcs) {
+		return out, err
+	}
+	completeFunc, err := generateFromTemplate(tmplCompleteFunction, in)
+	if err != nil {
+		return "", err
+	}
+	return out + "\n" + completeFunc, nil
+}
+
+// splitCompleters separates the completion functions referred to by the
+// complete directives, which are not exposed as commands, from the other
+// functions of the package.
+func splitCompleters(pkg *parser.PackageFunctions) (*parser.PackageFunctions, map[string]*parser.PkgFunc) {
+	referred := make(map[string]struct{})
+	for _, fn := range pkg.Functions {
+		names, err := getCompleterNames(fn)
+		if err != nil {
+			// the error is reported when the function is generated
+			continue
+		}
+		for _, name := range names {
+			referred[name] = struct{}{}
+		}
+	}
+	commands := *pkg
+	commands.Functions = make([]*parser.PkgFunc, 0, len(pkg.Functions))
+	completers := make(map[string]*parser.PkgFunc)
+	for _, fn := range pkg.Functions {
+		if _, ok := referred[fn.Name]; ok && isCompleter(fn) {
+			completers[fn.Name] = fn
+			continue
+		}
+		commands.Functions = append(commands.Functions, fn)
+	}
+	return &commands, completers
+}
+
+// getCompleters returns the callees of the completion functions of the
+// function parameters.
+func getCompleters(fn *parser.PkgFunc, completers map[string]*parser.PkgFunc, lib *libraryImport) (map[string]string, error) {
+	names, err := getCompleterNames(fn)
+	if err != nil {
+		return nil, err
+	}
+	callees := make(map[string]string, len(names))
+	for param, name := range names {
+		completer, ok := completers[name]
+		if !ok {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" of the parameter %s refers to the function %s, but no function with the signature func(prefix string) []string was found under this name", completeDirective, param, name)
+		}
+		callees[param] = lib.qualify(completer.Callee())
+	}
+	return callees, nil
+}
+
+// isCompleter reports whether the function has the signature of the
+// completion functions: func(prefix string) []string.
+func isCompleter(fn *parser.PkgFunc) bool {
+	return len(fn.Parameters) == 1 && fn.Parameters[0].Type.ToString() == "string" &&
+		len(fn.Results) == 1 && fn.Results[0] == "[]string"
+}
@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// countDirective marks the integer parameters whose value is the number of
+// times their flag is passed, e.g. "//gosif:count verbose" makes -vvv set
+// verbose to 3.
+const countDirective = "count"
+
+// lastWinsDirective allows to repeat the flags of the listed scalar
+// parameters (of all of them if none are listed), the last occurrence wins.
+const lastWinsDirective = "last-wins"
+
+// directiveParams returns the parameters listed in the directives name of
+// the function, all reports whether a directive without arguments is
+// present.
+func directiveParams(fn *parser.PkgFunc, name string) (params map[string]struct{}, all bool, err error) {
+	params = make(map[string]struct{})
+	for _, d := range fn.Directives {
+		if d.Name != name {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) == 0 {
+			all = true
+		}
+		for _, paramName := range fields {
+			if !hasParameter(fn, paramName) {
+				return nil, false, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", name, paramName)
+			}
+			params[paramName] = struct{}{}
+		}
+	}
+	return params, all, nil
+}
+
+func getCounters(fn *parser.PkgFunc) (map[string]struct{}, error) {
+	counters, _, err := directiveParams(fn, countDirective)
+	return counters, err
+}
+
+// getLastWins returns the scalar parameters whose flags can be repeated.
+func getLastWins(fn *parser.PkgFunc) (map[string]struct{}, error) {
+	lastWins, all, err := directiveParams(fn, lastWinsDirective)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range fn.Parameters {
+		if _, ok := lastWins[p.Name]; !ok && !all {
+			continue
+		}
+		if p.IsAnArray() {
+			if !all {
+				return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to the parameter %s of type %s, but repeated slice and array flags are always accumulated", lastWinsDirective, p.Name, p.Type.ToString())
+			}
+			delete(lastWins, p.Name)
+			continue
+		}
+		lastWins[p.Name] = struct{}{}
+	}
+	return lastWins, nil
+}
+
+// sepDirective sets the separator of the elements of a slice or an array
+// parameter passed as a single argument, e.g. "//gosif:sep paths :", the
+// separator "none" turns the splitting off.
+const sepDirective = "sep"
+
+const defaultSep = ","
+
+// getSeparators returns the separators of the slice and array parameters.
+// The elements of complex numbers are not split by default, since their
+// notation contains commas.
+func getSeparators(fn *parser.PkgFunc) (map[string]string, error) {
+	seps := make(map[string]string)
+	for _, p := range fn.Parameters {
+		if !p.IsAnArray() {
+			continue
+		}
+		switch p.Type.Base.CoreType {
+		case "complex64", "complex128":
+		default:
+			seps[p.Name] = defaultSep
+		}
+	}
+	for _, d := range fn.Directives {
+		if d.Name != sepDirective {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("the directive \"//gosif:%s %s\" is malformed: expected the format \"//gosif:%s <parameter> <separator>\"", sepDirective, d.Args, sepDirective)
+		}
+		name, sep := fields[0], fields[1]
+		param := getParameter(fn, name)
+		if param == nil {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", sepDirective, name)
+		}
+		if !param.IsAnArray() {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to the parameter %s of type %s, but only slices and arrays can be split", sepDirective, name, param.Type.ToString())
+		}
+		if sep == "none" {
+			delete(seps, name)
+			continue
+		}
+		seps[name] = sep
+	}
+	return seps, nil
+}
+
+// envDirective sets the environment variable of a parameter, e.g.
+// "//gosif:env env DEPLOY_ENV", the fallback is enabled for the parameter
+// even if it is off for the package.
+const envDirective = "env"
+
+// envPrefixDirective turns on the environment variables fallback for the
+// package, e.g. "//gosif:env-prefix MYAPP".
+const envPrefixDirective = "env-prefix"
+
+// getEnvVars returns the environment variables of the function parameters.
+func getEnvVars(fn *parser.PkgFunc, opts *Options) (map[string]string, error) {
+	envVars := make(map[string]string)
+	if opts != nil && len(opts.EnvPrefix) != 0 {
+		for _, p := range fn.Parameters {
+			envVars[p.Name] = strings.Join([]string{toEnvName(opts.EnvPrefix), toEnvName(fn.Name), toEnvName(p.Name)}, "_")
+		}
+	}
+	for _, d := range fn.Directives {
+		if d.Name != envDirective {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("the directive \"//gosif:%s %s\" is malformed: expected the format \"//gosif:%s <parameter> <variable>\"", envDirective, d.Args, envDirective)
+		}
+		if !hasParameter(fn, fields[0]) {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", envDirective, fields[0])
+		}
+		envVars[fields[0]] = fields[1]
+	}
+	return envVars, nil
+}
+
+// enumDirective lists the values accepted by a scalar parameter, e.g.
+// "//gosif:enum env dev staging prod", they are also offered by the shell
+// completion.
+const enumDirective = "enum"
+
+// getEnums returns the values accepted by the function parameters.
+func getEnums(fn *parser.PkgFunc) (map[string][]string, error) {
+	enums := make(map[string][]string)
+	for _, d := range fn.Directives {
+		if d.Name != enumDirective {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("the directive \"//gosif:%s %s\" is malformed: expected the format \"//gosif:%s <parameter> <value>...\"", enumDirective, d.Args, enumDirective)
+		}
+		param := getParameter(fn, fields[0])
+		if param == nil {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", enumDirective, fields[0])
+		}
+		if param.IsAnArray() {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to the parameter %s of type %s, but only scalar parameters can be enumerated", enumDirective, param.Name, param.Type.ToString())
+		}
+		for _, val := range fields[1:] {
+			if !isPlainWord(val) {
+				return nil, fmt.Errorf("the directive \"//gosif:%s\" lists the value %q of the parameter %s, but the values can only contain letters, digits and the characters %s", enumDirective, val, param.Name, plainWordPunct)
+			}
+		}
+		enums[param.Name] = fields[1:]
+	}
+	return enums, nil
+}
+
+// completeDirective sets the package function that completes the values of
+// a parameter, e.g. "//gosif:complete env CompleteEnvs", the function takes
+// the prefix typed so far and returns the candidates.
+const completeDirective = "complete"
+
+// getCompleterNames returns the names of the completion functions of the
+// function parameters.
+func getCompleterNames(fn *parser.PkgFunc) (map[string]string, error) {
+	completers := make(map[string]string)
+	for _, d := range fn.Directives {
+		if d.Name != completeDirective {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("the directive \"//gosif:%s %s\" is malformed: expected the format \"//gosif:%s <parameter> <function>\"", completeDirective, d.Args, completeDirective)
+		}
+		if !hasParameter(fn, fields[0]) {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", completeDirective, fields[0])
+		}
+		completers[fields[0]] = fields[1]
+	}
+	return completers, nil
+}
+
+// docDirective describes a parameter in the generated documentation, e.g.
+// "//gosif:doc env the environment to deploy to".
+const docDirective = "doc"
+
+// exampleDirective adds an example of the function arguments to the
+// generated documentation, e.g. "//gosif:example --env prod -r 3".
+const exampleDirective = "example"
+
+// getParamDocs returns the descriptions of the function parameters.
+func getParamDocs(fn *parser.PkgFunc) (map[string]string, error) {
+	docs := make(map[string]string)
+	for _, d := range fn.Directives {
+		if d.Name != docDirective {
+			continue
+		}
+		fields := d.Fields()
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("the directive \"//gosif:%s %s\" is malformed: expected the format \"//gosif:%s <parameter> <description>\"", docDirective, d.Args, docDirective)
+		}
+		if !hasParameter(fn, fields[0]) {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", docDirective, fields[0])
+		}
+		docs[fields[0]] = strings.TrimSpace(strings.TrimPrefix(d.Args, fields[0]))
+	}
+	return docs, nil
+}
+
+// interactiveDirective turns on the prompts for the missing required flags,
+// either for the whole package or for a function, if the standard input is a
+// terminal.
+const interactiveDirective = "interactive"
+
+// menuDirective turns on the menu of the package functions, shown on a
+// terminal if no function name is passed.
+const menuDirective = "menu"
+
+// plainWordPunct are the punctuation characters allowed in the enumerated
+// values, the other ones would have to be quoted in the completion scripts.
+const plainWordPunct = "_-.:/@+"
+
+func isPlainWord(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(plainWordPunct, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedNames(names map[string]struct{}) []string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func hasParameter(fn *parser.PkgFunc, name string) bool {
+	return getParameter(fn, name) != nil
+}
+
+func getParameter(fn *parser.PkgFunc, name string) *parser.FuncParam {
+	for _, p := range fn.Parameters {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func checkCounterType(param *parser.FuncParam) error {
+	if param.IsAnArray() || param.Type.IsPointer {
+		return fmt.Errorf("expected an integer type, got %s", param.Type.ToString())
+	}
+	switch param.Type.Base.CoreType {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return nil
+	default:
+		return fmt.Errorf("expected an integer type, got %s", param.Type.ToString())
+	}
+}
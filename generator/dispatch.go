@@ -0,0 +1,514 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// The exit codes of the generated binary.
+const (
+	gosif_ExitOK = 0
+	// gosif_ExitFailure is returned if the function panics or returns a
+	// non-nil error
+	gosif_ExitFailure = 1
+	// gosif_ExitUsage is returned if the command line is invalid, e.g. an
+	// unknown function or flag, or a flag value that cannot be cast
+	gosif_ExitUsage = 2
+)
+
+// gosif_UtilRun runs the function and turns a panic into an error, the
+// stack trace is added to it if the GOSIF_TRACEBACK environment variable is
+// set to 1.
+func gosif_UtilRun(fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if os.Getenv("GOSIF_TRACEBACK") == "1" {
+			err = fmt.Errorf("panic: %v\n\n%s", r, debug.Stack())
+			return
+		}
+		err = fmt.Errorf("panic: %v (set GOSIF_TRACEBACK=1 to print the stack trace)", r)
+	}()
+	return fn()
+}
+
+type gosif_ReadFlag struct {
+	PassedFlag string
+	Args       []string
+	// Source describes where the arguments were read from if they were not
+	// passed on the command line, it is reported in the cast errors
+	Source string
+}
+
+// gosif_ConfigPath is the path to the configuration file passed with the
+// --config option.
+var gosif_ConfigPath string
+
+// gosif_Interactive is set by the --interactive option, the missing required
+// flags are then prompted for if the standard input is a terminal.
+var gosif_Interactive bool
+
+// gosif_UtilExtractInteractive removes the --interactive option passed before
+// the function name, along with the other options, from the command line
+// arguments and reports whether it was found.
+func gosif_UtilExtractInteractive(args []string) ([]string, bool) {
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--interactive":
+			rest := append([]string{}, args[:i]...)
+			return append(rest, args[i+1:]...), true
+		case args[i] == "--config":
+			i++
+		case strings.HasPrefix(args[i], "--config="):
+		default:
+			return args, false
+		}
+	}
+	return args, false
+}
+
+var gosif_PromptReader *bufio.Reader
+
+// gosif_UtilShouldPrompt reports whether the missing required flags should
+// be prompted for: the interactive mode is turned on either with the
+// --interactive option or with a directive, and it never prompts if the
+// standard input is not a terminal.
+func gosif_UtilShouldPrompt(enabled bool) bool {
+	if !enabled && !gosif_Interactive {
+		return false
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// the null device is a character device too
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return false
+	}
+	if gosif_PromptReader == nil {
+		gosif_PromptReader = bufio.NewReader(os.Stdin)
+	}
+	return true
+}
+
+// gosif_Menu is set when the function was chosen from the menu, the command
+// line running it without the prompts is then shown.
+var gosif_Menu bool
+
+// gosif_Prompted are the flags whose values were prompted for.
+var gosif_Prompted []gosif_ReadFlag
+
+// gosif_MenuItem is a function listed in the menu.
+type gosif_MenuItem struct {
+	Name    string
+	Summary string
+}
+
+// gosif_UtilMenu lists the functions and asks to choose one either by its
+// number or by a prefix of its name, until a single function is chosen.
+func gosif_UtilMenu(in *bufio.Reader, out io.Writer, items []gosif_MenuItem) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("no function name passed")
+	}
+	width := 0
+	for _, item := range items {
+		if len(item.Name) > width {
+			width = len(item.Name)
+		}
+	}
+	fmt.Fprintln(out, "Functions:")
+	for i, item := range items {
+		line := fmt.Sprintf("%3d) %-*s  %s", i+1, width, item.Name, item.Summary)
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+	for {
+		fmt.Fprint(out, "Choose a function by its number or name: ")
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				fmt.Fprintln(out)
+				return "", fmt.Errorf("no function name passed")
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(line); err == nil {
+			if n < 1 || n > len(items) {
+				fmt.Fprintf(out, "expected a number from 1 to %d\n", len(items))
+				continue
+			}
+			return items[n-1].Name, nil
+		}
+		matches := make([]string, 0, 1)
+		for _, item := range items {
+			if item.Name == line {
+				matches = []string{item.Name}
+				break
+			}
+			if strings.HasPrefix(strings.ToLower(item.Name), strings.ToLower(line)) {
+				matches = append(matches, item.Name)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(out, "unknown function %s\n", line)
+		case 1:
+			return matches[0], nil
+		default:
+			fmt.Fprintf(out, "an ambiguous name %s, it may refer to %s\n", line, strings.Join(matches, ", "))
+		}
+	}
+}
+
+// gosif_UtilShowCommandLine prints the command line that runs the function
+// chosen from the menu with the prompted values, if any.
+func gosif_UtilShowCommandLine(stream io.Writer) {
+	if !gosif_Menu {
+		return
+	}
+	words := []string{os.Args[0]}
+	if len(gosif_ConfigPath) != 0 {
+		words = append(words, "--config", gosif_ConfigPath)
+	}
+	words = append(words, os.Args[1:]...)
+	for _, f := range gosif_Prompted {
+		words = append(words, f.PassedFlag)
+		words = append(words, f.Args...)
+	}
+	for i, word := range words {
+		words[i] = gosif_UtilShellQuote(word)
+	}
+	fmt.Fprintf(stream, "To run it without the prompts:\n\t%s\n", strings.Join(words, " "))
+}
+
+// gosif_UtilShellQuote quotes the word for a POSIX shell unless it consists
+// only of the characters that do not need quoting.
+func gosif_UtilShellQuote(word string) string {
+	if len(word) != 0 && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", "'\\''") + "'"
+}
+
+// gosif_UtilSuggest returns the candidate closest to the mistyped name by the
+// edit distance, if it is close enough. The names are compared ignoring the
+// case, the dashes and the underscores, so that e.g. dry_run matches both
+// dryRun and dry-run.
+func gosif_UtilSuggest(name string, candidates []string) (string, bool) {
+	normalized := gosif_UtilNormalizeName(name)
+	maxDistance := len(normalized) / 3
+	best, bestDistance := "", maxDistance+1
+	for _, c := range candidates {
+		d := gosif_UtilEditDistance(normalized, gosif_UtilNormalizeName(c))
+		if d > bestDistance || (d == bestDistance && (len(c) < len(best) || (len(c) == len(best) && c > best))) {
+			continue
+		}
+		best, bestDistance = c, d
+	}
+	return best, bestDistance <= maxDistance
+}
+
+func gosif_UtilNormalizeName(name string) []rune {
+	normalized := make([]rune, 0, len(name))
+	for _, r := range strings.ToLower(name) {
+		if r != '-' && r != '_' {
+			normalized = append(normalized, r)
+		}
+	}
+	return normalized
+}
+
+// gosif_UtilEditDistance returns the edit distance between a and b: the
+// number of the inserted, deleted and substituted letters, with the
+// transpositions of two adjacent letters counted as single edits.
+func gosif_UtilEditDistance(a []rune, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := rows[i-1][j-1] + cost
+			if rows[i-1][j]+1 < d {
+				d = rows[i-1][j] + 1
+			}
+			if rows[i][j-1]+1 < d {
+				d = rows[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && rows[i-2][j-2]+1 < d {
+				d = rows[i-2][j-2] + 1
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+const gosifDispatchFuncs = `
+// The exit codes of the generated binary.
+const (
+	gosif_ExitOK = 0
+	// gosif_ExitFailure is returned if the function panics or returns a
+	// non-nil error
+	gosif_ExitFailure = 1
+	// gosif_ExitUsage is returned if the command line is invalid, e.g. an
+	// unknown function or flag, or a flag value that cannot be cast
+	gosif_ExitUsage = 2
+)
+
+// gosif_UtilRun runs the function and turns a panic into an error, the
+// stack trace is added to it if the GOSIF_TRACEBACK environment variable is
+// set to 1.
+func gosif_UtilRun(fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if os.Getenv("GOSIF_TRACEBACK") == "1" {
+			err = fmt.Errorf("panic: %v\n\n%s", r, debug.Stack())
+			return
+		}
+		err = fmt.Errorf("panic: %v (set GOSIF_TRACEBACK=1 to print the stack trace)", r)
+	}()
+	return fn()
+}
+
+type gosif_ReadFlag struct {
+	PassedFlag string
+	Args       []string
+	// Source describes where the arguments were read from if they were not
+	// passed on the command line, it is reported in the cast errors
+	Source string
+}
+
+// gosif_ConfigPath is the path to the configuration file passed with the
+// --config option.
+var gosif_ConfigPath string
+
+// gosif_Interactive is set by the --interactive option, the missing required
+// flags are then prompted for if the standard input is a terminal.
+var gosif_Interactive bool
+
+// gosif_UtilExtractInteractive removes the --interactive option passed before
+// the function name, along with the other options, from the command line
+// arguments and reports whether it was found.
+func gosif_UtilExtractInteractive(args []string) ([]string, bool) {
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--interactive":
+			rest := append([]string{}, args[:i]...)
+			return append(rest, args[i+1:]...), true
+		case args[i] == "--config":
+			i++
+		case strings.HasPrefix(args[i], "--config="):
+		default:
+			return args, false
+		}
+	}
+	return args, false
+}
+
+var gosif_PromptReader *bufio.Reader
+
+// gosif_UtilShouldPrompt reports whether the missing required flags should
+// be prompted for: the interactive mode is turned on either with the
+// --interactive option or with a directive, and it never prompts if the
+// standard input is not a terminal.
+func gosif_UtilShouldPrompt(enabled bool) bool {
+	if !enabled && !gosif_Interactive {
+		return false
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// the null device is a character device too
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return false
+	}
+	if gosif_PromptReader == nil {
+		gosif_PromptReader = bufio.NewReader(os.Stdin)
+	}
+	return true
+}
+
+// gosif_Menu is set when the function was chosen from the menu, the command
+// line running it without the prompts is then shown.
+var gosif_Menu bool
+
+// gosif_Prompted are the flags whose values were prompted for.
+var gosif_Prompted []gosif_ReadFlag
+
+// gosif_MenuItem is a function listed in the menu.
+type gosif_MenuItem struct {
+	Name    string
+	Summary string
+}
+
+// gosif_UtilMenu lists the functions and asks to choose one either by its
+// number or by a prefix of its name, until a single function is chosen.
+func gosif_UtilMenu(in *bufio.Reader, out io.Writer, items []gosif_MenuItem) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("no function name passed")
+	}
+	width := 0
+	for _, item := range items {
+		if len(item.Name) > width {
+			width = len(item.Name)
+		}
+	}
+	fmt.Fprintln(out, "Functions:")
+	for i, item := range items {
+		line := fmt.Sprintf("%3d) %-*s  %s", i+1, width, item.Name, item.Summary)
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+	for {
+		fmt.Fprint(out, "Choose a function by its number or name: ")
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				fmt.Fprintln(out)
+				return "", fmt.Errorf("no function name passed")
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(line); err == nil {
+			if n < 1 || n > len(items) {
+				fmt.Fprintf(out, "expected a number from 1 to %d\n", len(items))
+				continue
+			}
+			return items[n-1].Name, nil
+		}
+		matches := make([]string, 0, 1)
+		for _, item := range items {
+			if item.Name == line {
+				matches = []string{item.Name}
+				break
+			}
+			if strings.HasPrefix(strings.ToLower(item.Name), strings.ToLower(line)) {
+				matches = append(matches, item.Name)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			fmt.Fprintf(out, "unknown function %s\n", line)
+		case 1:
+			return matches[0], nil
+		default:
+			fmt.Fprintf(out, "an ambiguous name %s, it may refer to %s\n", line, strings.Join(matches, ", "))
+		}
+	}
+}
+
+// gosif_UtilShowCommandLine prints the command line that runs the function
+// chosen from the menu with the prompted values, if any.
+func gosif_UtilShowCommandLine(stream io.Writer) {
+	if !gosif_Menu {
+		return
+	}
+	words := []string{os.Args[0]}
+	if len(gosif_ConfigPath) != 0 {
+		words = append(words, "--config", gosif_ConfigPath)
+	}
+	words = append(words, os.Args[1:]...)
+	for _, f := range gosif_Prompted {
+		words = append(words, f.PassedFlag)
+		words = append(words, f.Args...)
+	}
+	for i, word := range words {
+		words[i] = gosif_UtilShellQuote(word)
+	}
+	fmt.Fprintf(stream, "To run it without the prompts:\n\t%s\n", strings.Join(words, " "))
+}
+
+// gosif_UtilShellQuote quotes the word for a POSIX shell unless it consists
+// only of the characters that do not need quoting.
+func gosif_UtilShellQuote(word string) string {
+	if len(word) != 0 && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", "'\\''") + "'"
+}
+
+// gosif_UtilSuggest returns the candidate closest to the mistyped name by the
+// edit distance, if it is close enough. The names are compared ignoring the
+// case, the dashes and the underscores, so that e.g. dry_run matches both
+// dryRun and dry-run.
+func gosif_UtilSuggest(name string, candidates []string) (string, bool) {
+	normalized := gosif_UtilNormalizeName(name)
+	maxDistance := len(normalized) / 3
+	best, bestDistance := "", maxDistance+1
+	for _, c := range candidates {
+		d := gosif_UtilEditDistance(normalized, gosif_UtilNormalizeName(c))
+		if d > bestDistance || (d == bestDistance && (len(c) < len(best) || (len(c) == len(best) && c > best))) {
+			continue
+		}
+		best, bestDistance = c, d
+	}
+	return best, bestDistance <= maxDistance
+}
+
+func gosif_UtilNormalizeName(name string) []rune {
+	normalized := make([]rune, 0, len(name))
+	for _, r := range strings.ToLower(name) {
+		if r != '-' && r != '_' {
+			normalized = append(normalized, r)
+		}
+	}
+	return normalized
+}
+
+// gosif_UtilEditDistance returns the edit distance between a and b: the
+// number of the inserted, deleted and substituted letters, with the
+// transpositions of two adjacent letters counted as single edits.
+func gosif_UtilEditDistance(a []rune, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := rows[i-1][j-1] + cost
+			if rows[i-1][j]+1 < d {
+				d = rows[i-1][j] + 1
+			}
+			if rows[i][j-1]+1 < d {
+				d = rows[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && rows[i-2][j-2]+1 < d {
+				d = rows[i-2][j-2] + 1
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}`
+
+// gosifDispatchFuncsImports are the packages used by gosifDispatchFuncs
+// besides fmt and os.
+var gosifDispatchFuncsImports = []string{"bufio", "io", "runtime/debug", "strconv", "strings"}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// packageDoc describes the commands of a generated binary for the
+// documentation.
+type packageDoc struct {
+	// Prog is the name of the binary
+	Prog     string
+	Commands []*commandDoc
+	// HasConfig is set if the flags values can be read from a configuration
+	// file passed with the --config option
+	HasConfig bool
+}
+
+// commandDoc describes a command for the documentation.
+type commandDoc struct {
+	Name string
+	// Function is the name of the Go function
+	Function string
+	// Summary is the first sentence of the function doc comment
+	Summary string
+	Doc     string
+	Usage   string
+	Options []*optionDoc
+	// Examples are the command lines listed with the example directives
+	Examples []string
+}
+
+// optionDoc describes a flag for the documentation.
+type optionDoc struct {
+	Name string
+	// Parameter is the name of the Go function parameter
+	Parameter string
+	ShortName string
+	Type      string
+	Required  bool
+	// Default is the value of the optional flag that is not passed, it is
+	// empty for the pointers, which are left nil
+	Default string
+	EnvVar  string
+	Choices []string
+	// Description is set with the doc directive
+	Description string
+}
+
+// docsWriters write the documentation in the supported formats.
+var docsWriters = map[string]func(doc *packageDoc, outDir string) error{
+	"man":      writeManPages,
+	"markdown": writeMarkdown,
+	"json":     writeSpec,
+}
+
+// DocsFormats returns the names of the supported documentation formats.
+func DocsFormats() []string {
+	formats := make([]string, 0, len(docsWriters))
+	for format := range docsWriters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// GenerateDocs writes the documentation of the commands exposed for the
+// package located in dir, either a main or a library package, to outDir.
+func GenerateDocs(dir string, outDir string, format string, opts *Options) error {
+	writeDocs, ok := docsWriters[format]
+	if !ok {
+		return fmt.Errorf("unknown documentation format %s, expected one of %v", format, DocsFormats())
+	}
+	packages, err := parser.ParsePackagesFunctions(dir, opts.parserConfig())
+	if err != nil {
+		return err
+	}
+	pkg := packages.FindPackage(dir)
+	if pkg == nil {
+		pkg = packages.MainPackage
+	}
+	if pkg == nil {
+		return fmt.Errorf("no package was found in %s", dir)
+	}
+	prog := ""
+	if opts != nil {
+		prog = opts.ProgName
+	}
+	if len(prog) == 0 {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		prog = filepath.Base(absDir)
+	}
+	doc, err := getPackageDoc(pkg, prog, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create the output directory %s: %v", outDir, err)
+	}
+	return writeDocs(doc, outDir)
+}
+
+// getPackageDoc collects the documentation of the functions exposed as
+// commands, the functions that cannot be exposed are skipped.
+func getPackageDoc(pkg *parser.PackageFunctions, prog string, opts *Options) (*packageDoc, error) {
+	opts = opts.forPackage(pkg)
+	pkg, _ = splitCompleters(pkg)
+	pkg = dropClashingCommands(pkg, opts)
+	doc := getCommandsDoc(pkg.Functions, prog, opts)
+	if len(doc.Commands) == 0 {
+		return nil, fmt.Errorf("no functions to document were found in the package %s", pkg.PackageName)
+	}
+	return doc, nil
+}
+
+// getCommandsDoc collects the documentation of the functions, the functions
+// that cannot be exposed are skipped.
+func getCommandsDoc(functions []*parser.PkgFunc, prog string, opts *Options) *packageDoc {
+	doc := &packageDoc{
+		Prog:     prog,
+		Commands: make([]*commandDoc, 0, len(functions)),
+	}
+	for _, fn := range functions {
+		cmd, err := getCommandDoc(fn, prog, opts)
+		if err != nil {
+			log.Printf("[WARN]: skipping function %s: %v", fn.Name, err)
+			continue
+		}
+		doc.Commands = append(doc.Commands, cmd)
+		if len(cmd.Options) != 0 {
+			doc.HasConfig = true
+		}
+	}
+	return doc
+}
+
+func getCommandDoc(fn *parser.PkgFunc, prog string, opts *Options) (*commandDoc, error) {
+	processedFn, err := extractDataFromParsedFunction(fn, opts)
+	if err != nil {
+		return nil, err
+	}
+	paramDocs, err := getParamDocs(fn)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]*FuncParamData, 0, len(fn.Parameters))
+	params = append(params, processedFn.RequiredParams...)
+	params = append(params, processedFn.OptionalParams...)
+	positional, err := getPositionalSpec(processedFn, params)
+	if err != nil {
+		return nil, err
+	}
+	name := opts.cliName(fn.Name)
+	cmd := &commandDoc{
+		Name:     name,
+		Function: fn.Name,
+		Summary:  docSummary(fn.Doc),
+		Doc:      fn.Doc,
+		Usage:    positional.usage(name),
+		Options:  make([]*optionDoc, 0, len(params)),
+	}
+	for _, rawParam := range fn.Parameters {
+		for _, p := range params {
+			if p.RawParam.Name == rawParam.Name {
+				option := getOptionDoc(p)
+				option.Description = paramDocs[p.RawParam.Name]
+				cmd.Options = append(cmd.Options, option)
+			}
+		}
+	}
+	for _, d := range fn.Directives {
+		if d.Name == exampleDirective {
+			cmd.Examples = append(cmd.Examples, strings.TrimSpace(strings.Join([]string{prog, name, d.Args}, " ")))
+		}
+	}
+	return cmd, nil
+}
+
+func getOptionDoc(p *FuncParamData) *optionDoc {
+	option := &optionDoc{
+		Name:      p.Flag.CLIName,
+		Parameter: p.RawParam.Name,
+		Type:      p.Flag.Type,
+		Required:  !p.IsOptional,
+		EnvVar:    p.Flag.EnvVar,
+		Choices:   p.Flag.Choices,
+	}
+	if p.Flag.ShortName != nil && *p.Flag.ShortName != p.Flag.CLIName {
+		option.ShortName = *p.Flag.ShortName
+	}
+	switch {
+	case p.IsCounter:
+		option.Default = "0"
+	case p.IsOptional && !p.RawParam.Type.IsPointer:
+		option.Default = "false"
+	}
+	return option
+}
+
+// docSummary returns the first sentence of a doc comment, the sentence ends
+// with a period followed by a capital letter, so that abbreviations such as
+// "e.g." do not end it.
+func docSummary(doc string) string {
+	paragraph := strings.SplitN(doc, "\n\n", 2)[0]
+	words := strings.Fields(paragraph)
+	for i := 0; i+1 < len(words); i++ {
+		next := []rune(words[i+1])
+		if strings.HasSuffix(words[i], ".") && unicode.IsUpper(next[0]) {
+			words = words[:i+1]
+			break
+		}
+	}
+	return strings.TrimSuffix(strings.Join(words, " "), ".")
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDocSummary(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "Deploy deploys the service. It waits for the replicas.",
+			expected: "Deploy deploys the service",
+		},
+		{
+			in:       "Deploy deploys the service\nto the environment.\n\nThe tags are attached to the release.",
+			expected: "Deploy deploys the service to the environment",
+		},
+		{
+			in:       "Resize resizes the image (e.g. to 10x10)",
+			expected: "Resize resizes the image (e.g. to 10x10)",
+		},
+		{
+			in:       "Ping pings the host. then exits.",
+			expected: "Ping pings the host. then exits",
+		},
+		{
+			in:       "",
+			expected: "",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := docSummary(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual summary %q and expected summary %q are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "--dry-run",
+			expected: `\-\-dry\-run`,
+		},
+		{
+			in:       `C:\dir`,
+			expected: `C:\edir`,
+		},
+		{
+			in:       "first line\n.hidden\n'quoted",
+			expected: "first line\n\\&.hidden\n\\&'quoted",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := roffEscape(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual text %q and expected text %q are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "a|b",
+			expected: `a\|b`,
+		},
+		{
+			in:       "first line\n  second line",
+			expected: "first line second line",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := markdownCell(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual text %q and expected text %q are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
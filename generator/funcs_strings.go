@@ -161,7 +161,7 @@ var funcCheckRequiredFlags predefinedFunc = predefinedFunc{
 	func gosif_CheckRequiredFlags(requiredFlags map[string]bool) error {
 		for flag, isPresent := range requiredFlags {
 			if !isPresent {
-				return fmt.Errorf("a required flag \"-%s\" was not passed", flag)
+				return gosif_UtilMissingFlag(flag)
 			}
 		}
 		return nil
@@ -171,7 +171,7 @@ var funcCheckRequiredFlags predefinedFunc = predefinedFunc{
 func gosif_CheckRequiredFlags(requiredFlags map[string]bool) error {
 	for flag, isPresent := range requiredFlags {
 		if !isPresent {
-			return fmt.Errorf("a required flag \"-%s\" was not passed", flag)
+			return gosif_UtilMissingFlag(flag)
 		}
 	}
 	return nil
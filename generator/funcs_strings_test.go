@@ -254,7 +254,7 @@ func Test_gosif_CheckRequiredflags(t *testing.T) {
 				"a": true,
 				"b": false,
 			},
-			expectedErr: fmt.Errorf("a required flag \"-b\" was not passed"),
+			expectedErr: fmt.Errorf("a required flag \"--b\" was not passed"),
 		},
 		{
 			in: map[string]bool{},
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -19,11 +20,64 @@ import (
 
 const outFileName = "main.gen.go"
 
-func GenerateScriptsForDir(dir string) error {
+// Options configure the code generation.
+type Options struct {
+	// BuildTags are the build tags that are considered satisfied when the
+	// package files are selected
+	BuildTags []string
+	// KebabCase turns on the kebab-case names of the commands and flags, the
+	// Go names are still accepted as aliases
+	KebabCase bool
+	// EnvPrefix turns on the environment variables fallback for all the
+	// flags, the variables are named <EnvPrefix>_<FUNC>_<FLAG>
+	EnvPrefix string
+	// ProgName is the name of the binary in the generated documentation,
+	// the package directory name is used if it is empty
+	ProgName string
+	// Interactive prompts for the missing required flags if the standard
+	// input is a terminal, without the --interactive option
+	Interactive bool
+	// Menu lets the user choose the function from a menu if no function name is
+	// passed and the standard input is a terminal
+	Menu bool
+}
+
+// forPackage returns the options amended with the package directives.
+func (o *Options) forPackage(pkg *parser.PackageFunctions) *Options {
+	pkgOpts := &Options{}
+	if o != nil {
+		*pkgOpts = *o
+	}
+	if pkg.HasDirective(kebabCaseDirective) {
+		pkgOpts.KebabCase = true
+	}
+	if d := pkg.Directive(envPrefixDirective); d != nil && len(d.Args) != 0 {
+		pkgOpts.EnvPrefix = d.Args
+	}
+	if pkg.HasDirective(interactiveDirective) {
+		pkgOpts.Interactive = true
+	}
+	if pkg.HasDirective(menuDirective) {
+		pkgOpts.Menu = true
+	}
+	return pkgOpts
+}
+
+func (o *Options) parserConfig() *parser.Config {
+	cfg := &parser.Config{
+		ExcludedFiles: []string{outFileName},
+	}
+	if o != nil {
+		cfg.BuildTags = o.BuildTags
+	}
+	return cfg
+}
+
+func GenerateScriptsForDir(dir string, opts *Options) error {
 	if err := removePreviousOutput(dir); err != nil {
 		return fmt.Errorf("failed to remove a previously generated file: %v", err)
 	}
-	packages, err := parser.ParsePackagesFunctions(dir)
+	packages, err := parser.ParsePackagesFunctions(dir, opts.parserConfig())
 	if err != nil {
 		return err
 	}
@@ -31,22 +85,29 @@ func GenerateScriptsForDir(dir string) error {
 	if mainPkg == nil {
 		return fmt.Errorf("main package was not found in %s", dir)
 	}
-	if len(mainPkg.Functions) == 0 {
+	return generateScripts(mainPkg, mainPkg.HasMain, nil, dir, opts)
+}
+
+func generateScripts(pkg *parser.PackageFunctions, hasMain bool, lib *libraryImport, outDir string, opts *Options) error {
+	if len(pkg.Functions) == 0 {
 		log.Println("gosif did not find any functions to process, nothing to generate")
 		return nil
 	}
+	opts = opts.forPackage(pkg)
+	pkg, completers := splitCompleters(pkg)
+	pkg = dropClashingCommands(pkg, opts)
 	// TODO: refactor into several methods
-	out, err := createMain(mainPkg)
+	out, err := createMain(pkg, completers, hasMain, lib, opts)
 	if err != nil {
 		return err
 	}
-	helpFuncs, err := generateHelpFunctions(mainPkg.Functions)
+	helpFuncs, err := generateHelpFunctions(pkg.Functions, opts)
 	if err != nil {
 		return err
 	}
 	out += helpFuncs
 
-	if err := writeToFile(filepath.Join(dir, outFileName), out); err != nil {
+	if err := writeToFile(filepath.Join(outDir, outFileName), out); err != nil {
 		return err
 	}
 	return nil
@@ -59,18 +120,46 @@ type FuncForGenerator struct {
 	Imports        map[string]struct{}
 }
 
-func extractDataFromParsedFunction(fn *parser.PkgFunc) (*FuncForGenerator, error) {
+func extractDataFromParsedFunction(fn *parser.PkgFunc, opts *Options) (*FuncForGenerator, error) {
 	data := &FuncForGenerator{
 		ParsedFunc:     fn,
 		OptionalParams: make([]*FuncParamData, 0),
 		RequiredParams: make([]*FuncParamData, 0),
 		Imports:        make(map[string]struct{}),
 	}
+	counters, err := getCounters(fn)
+	if err != nil {
+		return nil, err
+	}
+	envVars, err := getEnvVars(fn, opts)
+	if err != nil {
+		return nil, err
+	}
+	enums, err := getEnums(fn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getParamDocs(fn); err != nil {
+		return nil, err
+	}
+	flagOwners := make(cliNameOwners)
 	for i, param := range fn.Parameters {
-		paramData, err := extractDataFromFuncParam(param)
+		if name, owner, ok := flagOwners.claim(opts.cliNames(param.Name), param.Name); !ok {
+			return nil, fmt.Errorf("the flag --%s of the parameter %s clashes with the parameter %s", name, param.Name, owner)
+		}
+		paramData, err := extractDataFromFuncParam(param, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to analyse parameters #%d \"%s\": %v", i, param.Name, err)
 		}
+		if _, ok := counters[param.Name]; ok {
+			if err := checkCounterType(param); err != nil {
+				return nil, fmt.Errorf("the parameter %s cannot be a counter: %v", param.Name, err)
+			}
+			paramData.IsCounter = true
+			paramData.IsOptional = true
+		}
+		paramData.Flag.EnvVar = envVars[param.Name]
+		paramData.Flag.Choices = enums[param.Name]
 		if paramData.IsOptional {
 			data.OptionalParams = append(data.OptionalParams, paramData)
 		} else {
@@ -99,18 +188,22 @@ type FuncParamData struct {
 	RawParam   *parser.FuncParam
 	Flag       *types.Flag
 	IsOptional bool
-	Imports    []string
+	// IsCounter is set for the parameters marked with the count directive,
+	// their value is the number of times the flag is passed
+	IsCounter bool
+	Imports   []string
 }
 
-func extractDataFromFuncParam(param *parser.FuncParam) (*FuncParamData, error) {
+func extractDataFromFuncParam(param *parser.FuncParam, opts *Options) (*FuncParamData, error) {
 	if !isParamTypeKnown(param.Type.Base.CoreType) {
 		return nil, fmt.Errorf("type %s is unknown", param.Type.Base.CoreType)
 	}
 	data := &FuncParamData{
 		RawParam: param,
 		Flag: &types.Flag{
-			Name: param.Name,
-			Type: param.Type.ToString(),
+			Name:    param.Name,
+			CLIName: opts.cliName(param.Name),
+			Type:    param.Type.ToString(),
 		},
 		IsOptional: !isParameterRequired(param),
 		Imports:    getRequiredImportsForParam(param),
@@ -122,8 +215,8 @@ func generateShortFlagsNames(flags []*types.Flag) error {
 	nameFlagDict := make(map[string]*types.Flag)
 	names := make([]string, len(flags))
 	for i, f := range flags {
-		nameFlagDict[f.Name] = f
-		names[i] = f.Name
+		nameFlagDict[f.CLIName] = f
+		names[i] = f.CLIName
 	}
 	shortNames, err := trie.GetShortNames(names)
 	if err != nil {
@@ -168,56 +261,103 @@ func isParamTypeKnown(paramType string) bool {
 	return ok
 }
 
-func createMain(mainPkgFunction *parser.PackageFunctions) (string, error) {
+func createMain(mainPkgFunction *parser.PackageFunctions, completers map[string]*parser.PkgFunc, hasMain bool, lib *libraryImport, opts *Options) (string, error) {
 	outs := make([]string, 0, len(mainPkgFunction.Functions))
 	// TODO: move importsMap and castFuncsMap to output
-	importsMap := make(map[string]struct{})
+	importsMap := make(importSet)
+	importsMap.add(mainFuncImports...)
 	castFuncsMap := make(map[string]string)
 	indirFuncsMap := make(map[string]string)
 	predefinedFuncsMap := make(map[string]string)
 	treatedFunctions := make([]*parser.PkgFunc, 0, len(mainPkgFunction.Functions))
-	var shouldAppendParsingFunctions bool
+	completionFuncs := make([]completionFunc, 0, len(mainPkgFunction.Functions))
+	configKeys := make(map[string]struct{})
 	for _, rawFn := range mainPkgFunction.Functions {
-		processedFn, err := extractDataFromParsedFunction(rawFn)
+		processedFn, err := extractDataFromParsedFunction(rawFn, opts)
 		if err != nil {
 			log.Printf("[WARN]: skipping function %s: %v", rawFn.Name, err)
 			continue
 		}
-		for imp := range processedFn.Imports {
-			if _, ok := importsMap[imp]; !ok {
-				importsMap[imp] = struct{}{}
-			}
-		}
-		out, err := generateFromFunction(processedFn, castFuncsMap, indirFuncsMap, predefinedFuncsMap)
-		if len(out) != 0 {
-			shouldAppendParsingFunctions = true
+		fnCompleters, err := getCompleters(rawFn, completers, lib)
+		if err != nil {
+			log.Printf("[WARN]: skipping function %s: %v", rawFn.Name, err)
+			continue
 		}
+		out, err := generateFromFunction(processedFn, lib, opts, castFuncsMap, indirFuncsMap, predefinedFuncsMap)
 		if err != nil {
 			log.Printf("[WARN]: skipping function %s: %v", rawFn.Name, err)
 			continue
 		}
 		outs = append(outs, out)
+		for imp := range processedFn.Imports {
+			importsMap.add(imp)
+		}
 		treatedFunctions = append(treatedFunctions, rawFn)
+		completionFuncs = append(completionFuncs, getCompletionFunc(processedFn, fnCompleters, opts))
+		for key := range flagAliases(paramsFlags(processedFn), opts) {
+			configKeys[key] = struct{}{}
+		}
 	}
-	if shouldAppendParsingFunctions {
+	if hasParameters(treatedFunctions) {
 		outs = append(outs, gosifFuncs)
+		importsMap.add(gosifFuncsImports...)
+	}
+	outs = append(outs, gosifDispatchFuncs)
+	importsMap.add(gosifDispatchFuncsImports...)
+	builtinCases := []builtinCase{{tmpl: tmplMainFuncSpecCase}}
+	hasCompletionCommand := !hasCommand(treatedFunctions, completionCommand, opts)
+	if hasCompletionCommand {
+		builtinCases = append(builtinCases, builtinCase{tmpl: tmplMainFuncCompletionCase, commands: []string{completionCommand}})
+		if hasCompleters(completionFuncs) {
+			builtinCases = append(builtinCases, builtinCase{tmpl: tmplMainFuncCompleteCase})
+		}
+	} else {
+		log.Printf("[WARN]: the %s command is not generated, since it clashes with a function name", completionCommand)
 	}
-	out := strings.Join(outs, "\n")
-	mainOut, err := generateMainFunc(treatedFunctions, mainPkgFunction.HasMain)
+	versionCase := builtinCase{
+		tmpl:     tmplMainFuncVersionCase,
+		in:       &tmplMainFuncVersionCaseInput{HasCommand: true},
+		commands: []string{versionCommand, "--" + versionCommand},
+	}
+	if hasCommand(treatedFunctions, versionCommand, opts) {
+		log.Printf("[WARN]: the %s command is not generated, since it clashes with a function name, the build information is shown with --%s only", versionCommand, versionCommand)
+		versionCase.in = &tmplMainFuncVersionCaseInput{HasCommand: false}
+		versionCase.commands = []string{"--" + versionCommand}
+	}
+	builtinCases = append(builtinCases, versionCase)
+	specOut, err := generateSpecFunction(treatedFunctions, opts)
 	if err != nil {
 		return "", err
 	}
-	imports := make([]string, 0, len(importsMap))
-	for imp := range importsMap {
-		imports = append(imports, imp)
+	outs = append(outs, specOut)
+	importsMap.add(specImports...)
+	if hasCompletionCommand {
+		completionOut, err := generateCompletionFunction(completionFuncs, builtinCommands(builtinCases), hasParameters(treatedFunctions))
+		if err != nil {
+			return "", err
+		}
+		outs = append(outs, completionOut)
+		importsMap.add(completionImports...)
+	}
+	versionOut, err := generateVersionFunction()
+	if err != nil {
+		return "", err
+	}
+	outs = append(outs, versionOut)
+	importsMap.add(versionImports...)
+	out := strings.Join(outs, "\n")
+	mainOut, err := generateMainFunc(treatedFunctions, sortedNames(configKeys), hasMain, builtinCases, lib, opts)
+	if err != nil {
+		return "", err
 	}
 	fullOut, err := generateFromTemplate(tmplFullFile, &tmplFullFileInput{
 		MainFunc:        mainOut,
-		Imports:         imports,
+		Imports:         importsMap.sorted(),
 		Out:             out,
 		CastFuncs:       castFuncsMap,
 		IndirFuncs:      indirFuncsMap,
 		PredefinedFuncs: predefinedFuncsMap,
+		Library:         lib,
 	})
 	if err != nil {
 		return "", nil
@@ -230,34 +370,59 @@ func createMain(mainPkgFunction *parser.PackageFunctions) (string, error) {
 	return fullOutFormatted, nil
 }
 
-func generateHelpFunctions(functions []*parser.PkgFunc) (string, error) {
-	scriptsHelpFunc, err := generateScriptsHelpFunction(functions)
+// importSet is the set of the packages imported by the generated file.
+type importSet map[string]struct{}
+
+func (s importSet) add(imports ...string) {
+	for _, imp := range imports {
+		s[imp] = struct{}{}
+	}
+}
+
+func (s importSet) sorted() []string {
+	imports := make([]string, 0, len(s))
+	for imp := range s {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+func generateHelpFunctions(functions []*parser.PkgFunc, opts *Options) (string, error) {
+	scriptsHelpFunc, err := generateScriptsHelpFunction(functions, opts)
 	return scriptsHelpFunc, err
 }
 
-func generateScriptsHelpFunction(functions []*parser.PkgFunc) (string, error) {
+func generateScriptsHelpFunction(functions []*parser.PkgFunc, opts *Options) (string, error) {
 	scriptsNames := make([]string, len(functions))
 	for i, f := range functions {
-		scriptsNames[i] = f.Name
+		scriptsNames[i] = opts.cliName(f.Name)
 	}
 	in := &tmplScriptsHelpFunctionInput{
-		ScriptsNames: scriptsNames,
+		ScriptsNames:  scriptsNames,
+		HasConfig:     hasParameters(functions),
+		HasCompletion: !hasCommand(functions, completionCommand, opts),
+		Menu:          opts.Menu,
 	}
 	helpFunc, err := generateFromTemplate(tmplScriptsHelpFunction, in)
 	return helpFunc, err
 }
 
 // TODO: Refactor
-func generateFromFunction(fn *FuncForGenerator, castFuncsMap map[string]string, indirFuncsMap map[string]string, predefinedFuncsMap map[string]string) (string, error) {
+func generateFromFunction(fn *FuncForGenerator, lib *libraryImport, opts *Options, castFuncsMap map[string]string, indirFuncsMap map[string]string, predefinedFuncsMap map[string]string) (string, error) {
 	if len(fn.ParsedFunc.Parameters) == 0 {
 		return "", nil
 	}
+	seps, err := getSeparators(fn.ParsedFunc)
+	if err != nil {
+		return "", err
+	}
 	cases := make([]string, len(fn.OptionalParams)+len(fn.RequiredParams))
 	params := fn.RequiredParams[:]
 	params = append(params, fn.OptionalParams...)
 	for i, param := range params {
 		// TODO: generate during parameter parsing
-		paramCase, err := generateCase(param.RawParam, param.Flag)
+		paramCase, err := generateCase(param.RawParam, param.Flag, !param.IsOptional, seps[param.RawParam.Name])
 		if err != nil {
 			return "", fmt.Errorf("case generation failed: %v", err)
 		}
@@ -287,19 +452,51 @@ func generateFromFunction(fn *FuncForGenerator, castFuncsMap map[string]string,
 	for _, p := range fn.RequiredParams {
 		requiredFlags = append(requiredFlags, *p.Flag)
 	}
+	callArgs := make([]string, len(flags))
+	for i, f := range flags {
+		callArgs[i] = "flags." + f.Name
+	}
 	flagStructTmplInput := &funcFlagStructureTmplInput{
 		Flags:        flags,
 		FunctionName: fn.ParsedFunc.Name,
+		Callee:       lib.qualify(fn.ParsedFunc.Callee()),
+		Call:         callStatements(lib.qualify(fn.ParsedFunc.Callee()), callArgs, fn.ParsedFunc.Results),
 	}
 	out1, err := generateFromTemplate(tmplFuncFlagsStruct, flagStructTmplInput)
 	if err != nil {
 		return "", err
 	}
+	positional, err := getPositionalSpec(fn, params)
+	if err != nil {
+		return "", err
+	}
+	lastWins, err := getLastWins(fn.ParsedFunc)
+	if err != nil {
+		return "", err
+	}
+	prompts, err := getPrompts(fn)
+	if err != nil {
+		return "", err
+	}
+	aliases := flagAliases(flags, opts)
 	parseFlagsFuncTmplIn := &tmplParseFlagsFuncInput{
-		Cases:         cases,
-		FuncFlags:     flags,
-		RequiredFlags: requiredFlags,
-		FunctionName:  fn.ParsedFunc.Name,
+		Cases:             cases,
+		FlagAliases:       aliases,
+		SliceFlags:        sliceFlags(params),
+		BoolFlags:         boolFlags(params),
+		Negations:         negations(params, aliases, opts),
+		Env:               flagsEnvVars(flags),
+		Choices:           flagsChoices(flags),
+		CountFlags:        countFlags(params),
+		LastWinsFlags:     sortedNames(lastWins),
+		Positional:        positional.names(),
+		LeadingPositional: positional.Leading,
+		CollectRest:       positional.CollectRest,
+		RequiredFlags:     requiredFlags,
+		FunctionName:      fn.ParsedFunc.Name,
+		CommandNames:      opts.cliNames(fn.ParsedFunc.Name),
+		Interactive:       opts.Interactive || fn.ParsedFunc.Directive(interactiveDirective) != nil,
+		Prompts:           prompts,
 	}
 	out2, err := generateFromTemplate(tmplParseFlagsFunc, parseFlagsFuncTmplIn)
 	if err != nil {
@@ -310,7 +507,7 @@ func generateFromFunction(fn *FuncForGenerator, castFuncsMap map[string]string,
 	if err != nil {
 		return "", err
 	}
-	funcHelp, err := generateFuncHelpFunction(fn.ParsedFunc, flags, requiredFlags)
+	funcHelp, err := generateFuncHelpFunction(fn.ParsedFunc, opts, positional, flags, requiredFlags)
 	if err != nil {
 		return "", err
 	}
@@ -318,6 +515,32 @@ func generateFromFunction(fn *FuncForGenerator, castFuncsMap map[string]string,
 	return out, nil
 }
 
+// getPrompts describes the required flags, in the order of the function
+// parameters, for the interactive mode.
+func getPrompts(fn *FuncForGenerator) ([]tmplPromptInput, error) {
+	docs, err := getParamDocs(fn.ParsedFunc)
+	if err != nil {
+		return nil, err
+	}
+	prompts := make([]tmplPromptInput, 0, len(fn.RequiredParams))
+	for _, rawParam := range fn.ParsedFunc.Parameters {
+		for _, p := range fn.RequiredParams {
+			if p.RawParam.Name != rawParam.Name {
+				continue
+			}
+			prompts = append(prompts, tmplPromptInput{
+				Name:        p.Flag.CLIName,
+				Param:       p.Flag.Name,
+				Type:        p.Flag.Type,
+				Description: docs[p.RawParam.Name],
+				Choices:     p.Flag.Choices,
+				IsSlice:     p.RawParam.IsAnArray(),
+			})
+		}
+	}
+	return prompts, nil
+}
+
 func composeFlagsList(params []*FuncParamData, fn *FuncForGenerator) ([]types.Flag, error) {
 	flags := make([]types.Flag, 0, len(params))
 	for _, p := range fn.ParsedFunc.Parameters {
@@ -347,9 +570,115 @@ func composeFlagsList(params []*FuncParamData, fn *FuncForGenerator) ([]types.Fl
 	return flags, nil
 }
 
-func generateFuncHelpFunction(fn *parser.PkgFunc, flags []types.Flag, requiredFlags []types.Flag) (string, error) {
+// flagAliases maps the flag names accepted on the command line, including
+// the short names, to the names of the corresponding parameters.
+func flagAliases(flags []types.Flag, opts *Options) map[string]string {
+	aliases := make(map[string]string, len(flags))
+	for _, f := range flags {
+		for _, name := range opts.cliNames(f.Name) {
+			aliases[name] = f.Name
+		}
+		if f.ShortName != nil {
+			aliases[*f.ShortName] = f.Name
+		}
+	}
+	return aliases
+}
+
+// paramsFlags returns the flags of the function parameters.
+func paramsFlags(fn *FuncForGenerator) []types.Flag {
+	flags := make([]types.Flag, 0, len(fn.RequiredParams)+len(fn.OptionalParams))
+	for _, p := range fn.RequiredParams {
+		flags = append(flags, *p.Flag)
+	}
+	for _, p := range fn.OptionalParams {
+		flags = append(flags, *p.Flag)
+	}
+	return flags
+}
+
+// sliceFlags returns the names of the parameters that take several arguments.
+func sliceFlags(params []*FuncParamData) []string {
+	names := make([]string, 0)
+	for _, p := range params {
+		if p.RawParam.IsAnArray() {
+			names = append(names, p.RawParam.Name)
+		}
+	}
+	return names
+}
+
+// boolFlags returns the names of the parameters that can be set without an
+// argument.
+func boolFlags(params []*FuncParamData) []string {
+	names := make([]string, 0)
+	for _, p := range params {
+		if p.RawParam.Type.Base.CoreType == "bool" && !p.RawParam.IsAnArray() {
+			names = append(names, p.RawParam.Name)
+		}
+	}
+	return names
+}
+
+// negations maps the negated names of the boolean flags (no-<name>) to the
+// parameters names, skipping the ones that clash with the flags names.
+func negations(params []*FuncParamData, aliases map[string]string, opts *Options) map[string]string {
+	negated := make(map[string]string)
+	for _, p := range params {
+		if p.RawParam.Type.Base.CoreType != "bool" || p.RawParam.IsAnArray() {
+			continue
+		}
+		for _, name := range opts.cliNames(p.RawParam.Name) {
+			negatedName := "no-" + name
+			if clashing, ok := aliases[negatedName]; ok {
+				log.Printf("[WARN]: the flag --%s is not generated, since it clashes with the flag of the parameter %s", negatedName, clashing)
+				continue
+			}
+			negated[negatedName] = p.RawParam.Name
+		}
+	}
+	return negated
+}
+
+// flagsEnvVars maps the parameters names to their environment variables.
+func flagsEnvVars(flags []types.Flag) map[string]string {
+	envVars := make(map[string]string)
+	for _, f := range flags {
+		if len(f.EnvVar) != 0 {
+			envVars[f.Name] = f.EnvVar
+		}
+	}
+	return envVars
+}
+
+// flagsChoices maps the parameters names to the values they accept.
+func flagsChoices(flags []types.Flag) map[string][]string {
+	choices := make(map[string][]string)
+	for _, f := range flags {
+		if len(f.Choices) != 0 {
+			choices[f.Name] = f.Choices
+		}
+	}
+	return choices
+}
+
+// countFlags returns the names of the counter parameters.
+func countFlags(params []*FuncParamData) []string {
+	names := make([]string, 0)
+	for _, p := range params {
+		if p.IsCounter {
+			names = append(names, p.RawParam.Name)
+		}
+	}
+	return names
+}
+
+func generateFuncHelpFunction(fn *parser.PkgFunc, opts *Options, positional *positionalSpec, flags []types.Flag, requiredFlags []types.Flag) (string, error) {
+	commandName := opts.cliName(fn.Name)
 	in := &tmplFuncHelpFunctionInput{
 		FunctionName:  fn.Name,
+		CommandName:   commandName,
+		Usage:         positional.usage(commandName),
 		Flags:         flagsToHelpFlags(flags),
 		RequiredFlags: flagsToHelpFlags(requiredFlags),
 	}
@@ -364,10 +693,12 @@ func flagsToHelpFlags(flags []types.Flag) []helpFlagData {
 	helpFlags := make([]helpFlagData, len(flags))
 	for i, f := range flags {
 		helpFlags[i] = helpFlagData{
-			Name: f.Name,
-			Type: f.Type,
+			Name:    f.CLIName,
+			Type:    f.Type,
+			EnvVar:  f.EnvVar,
+			Choices: f.Choices,
 		}
-		if f.ShortName != nil && *f.ShortName != f.Name {
+		if f.ShortName != nil && *f.ShortName != f.CLIName {
 			helpFlags[i].ShortName = f.ShortName
 		}
 	}
@@ -457,7 +788,7 @@ func getRequiredImportsForParam(param *parser.FuncParam) []string {
 	return imports
 }
 
-func generateCase(param *parser.FuncParam, f *types.Flag) (string, error) {
+func generateCase(param *parser.FuncParam, f *types.Flag, isRequired bool, sep string) (string, error) {
 	if len(param.Type.Layers) > 1 {
 		return "", fmt.Errorf("NYI")
 	}
@@ -488,6 +819,7 @@ func generateCase(param *parser.FuncParam, f *types.Flag) (string, error) {
 			IndirectionLevel:     arrLayer.IndirectionLevel,
 			Payload:              argParsing,
 			BaseIndirectionLevel: param.Type.Base.IndirectionLevel,
+			Sep:                  sep,
 		}
 		if !arrLayer.ArrayConfig.IsSlice {
 			tmplArrayLayerIn.ArrInfo.ArrayLength = arrLayer.ArrayConfig.Length
@@ -498,10 +830,11 @@ func generateCase(param *parser.FuncParam, f *types.Flag) (string, error) {
 		}
 	}
 	tmplArgCastPostfixIn := &tmplArgCastPostfixInput{
-		FlagName:  f.Name,
-		InArray:   param.IsAnArray(),
-		IsPointer: param.Type.IsPointer,
-		BaseType:  param.Type.Base.CoreType,
+		FlagName:   f.Name,
+		CLIName:    f.CLIName,
+		InArray:    param.IsAnArray(),
+		IsPointer:  param.Type.IsPointer,
+		IsRequired: isRequired,
 	}
 	postfix, err := generateFromTemplate(tmplArgCastPostfix, tmplArgCastPostfixIn)
 	if err != nil {
@@ -644,31 +977,82 @@ func formatOutput(out string) (string, error) {
 
 func newFlag(p *parser.FuncParam) *types.Flag {
 	f := &types.Flag{
-		Name: p.Name,
-		Type: p.Type.ToString(),
+		Name:    p.Name,
+		CLIName: p.Name,
+		Type:    p.Type.ToString(),
 	}
 	return f
 }
 
-func generateMainFunc(scriptFuncs []*parser.PkgFunc, hasMain bool) (string, error) {
-	cases := make([]string, len(scriptFuncs))
+// builtinCase is a case of the main function handling a builtin command.
+type builtinCase struct {
+	tmpl *template.Template
+	in   interface{}
+	// commands are the command names handled by the case that are offered by
+	// the shell completion, they are left empty for the hidden commands
+	commands []string
+}
+
+// builtinCommands returns the command names handled by the builtin cases.
+func builtinCommands(cases []builtinCase) []string {
+	commands := make([]string, 0, len(cases))
+	for _, c := range cases {
+		commands = append(commands, c.commands...)
+	}
+	return commands
+}
+
+func generateMainFunc(scriptFuncs []*parser.PkgFunc, configKeys []string, hasMain bool, builtinCases []builtinCase, lib *libraryImport, opts *Options) (string, error) {
+	cases := make([]string, len(scriptFuncs), len(scriptFuncs)+len(builtinCases))
 	for i, fn := range scriptFuncs {
 		var err error
-		cases[i], err = generateMainFuncCase(fn)
+		cases[i], err = generateMainFuncCase(fn, lib, opts)
+		if err != nil {
+			return "", err
+		}
+	}
+	for _, c := range builtinCases {
+		out, err := generateFromTemplate(c.tmpl, c.in)
 		if err != nil {
 			return "", err
 		}
+		cases = append(cases, out)
 	}
 	mainIn := &mainFuncTmplInput{
-		Cases:   cases,
-		HasMain: hasMain,
+		Cases:     cases,
+		HasMain:   hasMain,
+		HasConfig:  hasParameters(scriptFuncs),
+		Commands:   make([]string, 0, len(scriptFuncs)),
+		ConfigKeys: configKeys,
+		Menu:       opts.Menu,
+		MenuItems:  make([]menuItem, 0, len(scriptFuncs)),
+	}
+	for _, fn := range scriptFuncs {
+		mainIn.Commands = append(mainIn.Commands, opts.cliNames(fn.Name)...)
+		mainIn.MenuItems = append(mainIn.MenuItems, menuItem{
+			Name:    opts.cliName(fn.Name),
+			Summary: docSummary(fn.Doc),
+		})
 	}
 	return generateFromTemplate(tmplMainFunc, mainIn)
 }
 
-func generateMainFuncCase(scriptFunc *parser.PkgFunc) (string, error) {
+// hasParameters reports whether any of the functions takes parameters.
+func hasParameters(functions []*parser.PkgFunc) bool {
+	for _, fn := range functions {
+		if len(fn.Parameters) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func generateMainFuncCase(scriptFunc *parser.PkgFunc, lib *libraryImport, opts *Options) (string, error) {
 	in := &mainFuncScriptCaseTmplInput{
 		FunctionName: scriptFunc.Name,
+		CommandNames: opts.cliNames(scriptFunc.Name),
+		Callee:       lib.qualify(scriptFunc.Callee()),
+		Call:         callStatements(lib.qualify(scriptFunc.Callee()), nil, scriptFunc.Results),
 	}
 	if len(scriptFunc.Parameters) == 0 {
 		scriptCase, err := generateFromTemplate(tmplMainFuncNoArgsScriptCase, in)
@@ -678,6 +1062,19 @@ func generateMainFuncCase(scriptFunc *parser.PkgFunc) (string, error) {
 	return scriptCase, err
 }
 
+// callStatements returns the statements calling the function and returning
+// its error: the last result if it is an error, nil otherwise.
+func callStatements(callee string, args []string, results []string) string {
+	call := fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))
+	if len(results) == 0 || results[len(results)-1] != "error" {
+		return call + "\nreturn nil"
+	}
+	if len(results) == 1 {
+		return "return " + call
+	}
+	return strings.Repeat("_, ", len(results)-1) + "err := " + call + "\nreturn err"
+}
+
 func generateFromTemplate(tmpl *template.Template, in interface{}) (string, error) {
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, in); err != nil {
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// libraryImport describes a non-main package whose exported functions are
+// exposed by a generated main package located in another directory.
+type libraryImport struct {
+	Path  string
+	Alias string
+}
+
+// qualify returns the expression that refers to the function name from the
+// generated file: the name itself for a main package and a qualified
+// identifier for a library package.
+func (l *libraryImport) qualify(name string) string {
+	if l == nil {
+		return name
+	}
+	return fmt.Sprintf("%s.%s", l.Alias, name)
+}
+
+// GenerateScriptsForPackage generates a standalone main package in outDir
+// that imports the non-main package located in pkgDir and exposes its
+// exported functions as commands.
+func GenerateScriptsForPackage(pkgDir string, outDir string, opts *Options) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create the output directory %s: %v", outDir, err)
+	}
+	if err := removePreviousOutput(outDir); err != nil {
+		return fmt.Errorf("failed to remove a previously generated file: %v", err)
+	}
+	packages, err := parser.ParsePackagesFunctions(pkgDir, opts.parserConfig())
+	if err != nil {
+		return err
+	}
+	pkg := packages.FindPackage(pkgDir)
+	if pkg == nil {
+		return fmt.Errorf("a non-main package was not found in %s", pkgDir)
+	}
+	importPath, err := resolveImportPath(pkgDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the import path of %s: %v", pkgDir, err)
+	}
+	hasMain, err := outDirHasMain(outDir, opts)
+	if err != nil {
+		return err
+	}
+	lib := &libraryImport{
+		Path:  importPath,
+		Alias: libraryAlias(pkg.PackageName),
+	}
+	return generateScripts(pkg, hasMain, lib, outDir, opts)
+}
+
+// libraryAlias returns the name under which the library package is imported.
+// The package is always aliased, so that its name clashes neither with the
+// packages imported by the generated code nor with its local variables.
+func libraryAlias(pkgName string) string {
+	return "gosif_" + pkgName
+}
+
+func outDirHasMain(outDir string, opts *Options) (bool, error) {
+	packages, err := parser.ParsePackagesFunctions(outDir, opts.parserConfig())
+	if err != nil {
+		return false, err
+	}
+	if pkg := packages.FindPackage(outDir); pkg != nil {
+		return false, fmt.Errorf("the output directory %s contains a non-main package %s", outDir, pkg.PackageName)
+	}
+	mainPkg := packages.MainPackage
+	if mainPkg == nil || filepath.Clean(mainPkg.PackageDir) != filepath.Clean(outDir) {
+		return false, nil
+	}
+	return mainPkg.HasMain, nil
+}
+
+// resolveImportPath looks up the go.mod file that the directory dir belongs
+// to and composes the import path of the package located in dir.
+func resolveImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	modDir := absDir
+	for {
+		modPath, err := readModulePath(filepath.Join(modDir, "go.mod"))
+		if err != nil {
+			return "", err
+		}
+		if len(modPath) != 0 {
+			rel, err := filepath.Rel(modDir, absDir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return modPath, nil
+			}
+			return fmt.Sprintf("%s/%s", modPath, filepath.ToSlash(rel)), nil
+		}
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return "", fmt.Errorf("go.mod file was not found")
+		}
+		modDir = parent
+	}
+}
+
+func readModulePath(goModPath string) (string, error) {
+	f, err := os.Open(goModPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		modPath := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		modPath = strings.Trim(modPath, "\"`")
+		if len(modPath) != 0 {
+			return modPath, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s does not declare a module path", goModPath)
+}
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLibraryAlias(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "ops",
+			expected: "gosif_ops",
+		},
+		{
+			in:       "strings",
+			expected: "gosif_strings",
+		},
+		{
+			in:       "flags",
+			expected: "gosif_flags",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := libraryAlias(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual alias %s and expected alias %s are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// roffEscape escapes the text for roff: the backslashes and the dashes are
+// escaped and the lines starting with a control character are protected.
+func roffEscape(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// docSentence capitalizes the text and ends it with a period, unless it
+// already ends with a punctuation mark.
+func docSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return text
+	}
+	text = strings.ToUpper(text[:1]) + text[1:]
+	if strings.ContainsAny(text[len(text)-1:], ".!?") {
+		return text
+	}
+	return text + "."
+}
+
+var manFuncs = template.FuncMap{
+	"roff":     roffEscape,
+	"upper":    strings.ToUpper,
+	"join":     strings.Join,
+	"sentence": docSentence,
+}
+
+var tmplManOption = template.Must(template.New("ManOption").Funcs(manFuncs).Parse(`
+{{- define "ManOption" -}}
+.TP
+{{if .ShortName}}\fB\-{{roff .ShortName}}\fR, {{end}}\fB\-\-{{roff .Name}}\fR \fI{{roff .Type}}\fR
+{{if .Description}}{{roff (sentence .Description)}} {{end}}{{if .Required}}Required.{{else if .Default}}Default: {{roff .Default}}.{{else}}Optional.{{end}}
+{{- if .Choices}} One of: {{roff (join .Choices ", ")}}.{{end}}
+{{- if .EnvVar}} Environment variable: \fB{{roff .EnvVar}}\fR.{{end}}
+{{- end -}}`))
+
+var tmplManIndex = template.Must(template.Must(tmplManOption.Clone()).New("ManIndex").Parse(`.TH {{roff (upper .Prog)}} 1 "" "{{roff .Prog}}" "User Commands"
+.SH NAME
+{{roff .Prog}} \- run the functions exposed by {{roff .Prog}}
+.SH SYNOPSIS
+.B {{roff .Prog}}
+{{- if .HasConfig}}
+[\fB\-\-config\fR \fIfile\fR]
+{{- end}}
+\fIfunction\fR [\fIflags\fR]
+.SH FUNCTIONS
+{{- range $cmd := .Commands}}
+.TP
+.B {{roff $cmd.Name}}
+{{if $cmd.Summary}}{{roff $cmd.Summary}}.{{else}}See {{roff $.Prog}}\-{{roff $cmd.Name}}(1).{{end}}
+{{- end}}
+.TP
+.B help
+Show the list of the functions, or the help message of a function if passed after its name.
+{{- if .HasConfig}}
+.SH OPTIONS
+.TP
+\fB\-\-config\fR \fIfile\fR
+Read the flags values from a JSON or INI file, the flags passed on the command line and the environment variables take precedence.
+{{- end}}
+.SH SEE ALSO
+{{range $i, $cmd := .Commands}}{{if $i}},
+{{end}}.BR {{roff $.Prog}}\-{{roff $cmd.Name}} (1)
+{{- end}}
+`))
+
+var tmplManCommand = template.Must(template.Must(tmplManOption.Clone()).New("ManCommand").Parse(`.TH {{roff (upper .Prog)}}\-{{roff (upper .Command.Name)}} 1 "" "{{roff .Prog}}" "User Commands"
+.SH NAME
+{{roff .Prog}}\-{{roff .Command.Name}} \- {{if .Command.Summary}}{{roff .Command.Summary}}{{else}}run the {{roff .Command.Name}} function{{end}}
+.SH SYNOPSIS
+.B {{roff .Prog}}
+{{roff .Command.Usage}}
+{{- if .Command.Doc}}
+.SH DESCRIPTION
+{{roff .Command.Doc}}
+{{- end}}
+{{- if .Command.Options}}
+.SH OPTIONS
+{{- range $option := .Command.Options}}
+{{template "ManOption" $option}}
+{{- end}}
+{{- end}}
+{{- if .Command.Examples}}
+.SH EXAMPLES
+.nf
+{{- range $example := .Command.Examples}}
+{{roff $example}}
+{{- end}}
+.fi
+{{- end}}
+.SH SEE ALSO
+.BR {{roff .Prog}} (1)
+`))
+
+type tmplManCommandInput struct {
+	Prog    string
+	Command *commandDoc
+}
+
+// writeManPages writes the section 1 man pages: <prog>.1 listing the
+// functions and <prog>-<function>.1 for every function.
+func writeManPages(doc *packageDoc, outDir string) error {
+	index, err := generateFromTemplate(tmplManIndex, doc)
+	if err != nil {
+		return err
+	}
+	if err := writeToFile(filepath.Join(outDir, doc.Prog+".1"), index); err != nil {
+		return err
+	}
+	for _, cmd := range doc.Commands {
+		page, err := generateFromTemplate(tmplManCommand, &tmplManCommandInput{
+			Prog:    doc.Prog,
+			Command: cmd,
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeToFile(filepath.Join(outDir, fmt.Sprintf("%s-%s.1", doc.Prog, cmd.Name)), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
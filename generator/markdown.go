@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// markdownCell escapes the text for a Markdown table cell: the pipes are
+// escaped and the text is kept on a single line.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// markdownOptionDescription returns the description of the flag followed by
+// its accepted values and its environment variable.
+func markdownOptionDescription(option *optionDoc) string {
+	parts := make([]string, 0, 3)
+	if len(option.Description) != 0 {
+		parts = append(parts, docSentence(option.Description))
+	}
+	if len(option.Choices) != 0 {
+		parts = append(parts, fmt.Sprintf("One of: %s.", strings.Join(option.Choices, ", ")))
+	}
+	if len(option.EnvVar) != 0 {
+		parts = append(parts, fmt.Sprintf("Environment variable: `%s`.", option.EnvVar))
+	}
+	return markdownCell(strings.Join(parts, " "))
+}
+
+var markdownFuncs = template.FuncMap{
+	"cell":        markdownCell,
+	"sentence":    docSentence,
+	"description": markdownOptionDescription,
+}
+
+var tmplMarkdownIndex = template.Must(template.New("MarkdownIndex").Funcs(markdownFuncs).Parse(`# {{.Prog}}
+
+Run a function with:
+
+` + "```" + `
+{{.Prog}}{{if .HasConfig}} [--config <file>]{{end}} <function> [flags]
+` + "```" + `
+{{- if .HasConfig}}
+
+The flags values can be read from a JSON or INI file passed with ` + "`--config`" + `, the flags passed on the command line and the environment variables take precedence.
+{{- end}}
+
+## Functions
+
+| Function | Description |
+| --- | --- |
+{{- range $cmd := .Commands}}
+| [{{$cmd.Name}}]({{$cmd.Name}}.md) | {{cell (sentence $cmd.Summary)}} |
+{{- end}}
+`))
+
+var tmplMarkdownCommand = template.Must(template.New("MarkdownCommand").Funcs(markdownFuncs).Parse(`# {{.Prog}} {{.Command.Name}}
+{{- if .Command.Doc}}
+
+{{.Command.Doc}}
+{{- end}}
+
+## Usage
+
+` + "```" + `
+{{.Prog}} {{.Command.Usage}}
+` + "```" + `
+{{- if .Command.Options}}
+
+## Flags
+
+| Name | Short name | Type | Required | Default | Description |
+| --- | --- | --- | --- | --- | --- |
+{{- range $option := .Command.Options}}
+| ` + "`--{{cell $option.Name}}`" + ` | {{if $option.ShortName}}` + "`-{{cell $option.ShortName}}`" + `{{end}} | ` + "`{{cell $option.Type}}`" + ` | {{if $option.Required}}yes{{else}}no{{end}} | {{if $option.Default}}` + "`{{cell $option.Default}}`" + `{{end}} | {{description $option}} |
+{{- end}}
+{{- end}}
+{{- if .Command.Examples}}
+
+## Examples
+
+` + "```" + `
+{{- range $example := .Command.Examples}}
+{{$example}}
+{{- end}}
+` + "```" + `
+{{- end}}
+
+See [{{.Prog}}](index.md) for the other functions.
+`))
+
+type tmplMarkdownCommandInput struct {
+	Prog    string
+	Command *commandDoc
+}
+
+// writeMarkdown writes the Markdown reference: index.md listing the functions
+// and <function>.md for every function.
+func writeMarkdown(doc *packageDoc, outDir string) error {
+	index, err := generateFromTemplate(tmplMarkdownIndex, doc)
+	if err != nil {
+		return err
+	}
+	if err := writeToFile(filepath.Join(outDir, "index.md"), index); err != nil {
+		return err
+	}
+	for _, cmd := range doc.Commands {
+		page, err := generateFromTemplate(tmplMarkdownCommand, &tmplMarkdownCommandInput{
+			Prog:    doc.Prog,
+			Command: cmd,
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeToFile(filepath.Join(outDir, cmd.Name+".md"), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
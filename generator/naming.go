@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// kebabCaseDirective turns on the kebab-case naming for the whole package.
+const kebabCaseDirective = "kebab-case"
+
+// toKebabCase converts a Go identifier to the kebab case, e.g. maxRetries
+// becomes max-retries and HTTPServer becomes http-server.
+func toKebabCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if r == '_' {
+			if sb.Len() != 0 && i != len(runes)-1 {
+				sb.WriteRune('-')
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i != 0 {
+			prev := runes[i-1]
+			startsWord := unicode.IsLower(prev) || unicode.IsDigit(prev)
+			endsAcronym := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if (startsWord || endsAcronym) && prev != '_' {
+				sb.WriteRune('-')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// cliName returns the name under which a Go identifier (a function or a
+// parameter name) is exposed on the command line.
+func (o *Options) cliName(name string) string {
+	if o == nil || !o.KebabCase {
+		return name
+	}
+	return toKebabCase(name)
+}
+
+// cliNames returns the names accepted on the command line for a Go identifier:
+// the converted name first, followed by the Go name if it differs.
+func (o *Options) cliNames(name string) []string {
+	converted := o.cliName(name)
+	if converted == name {
+		return []string{name}
+	}
+	return []string{converted, name}
+}
+
+// cliNameOwners maps the command-line names to the Go identifiers they were
+// converted from, so that the identifiers whose names clash once converted
+// (e.g. FooBar and Foo_Bar in the kebab-case mode) are detected.
+type cliNameOwners map[string]string
+
+// claim registers the command-line names of the Go identifier owner, unless
+// one of them is already taken: the clashing name and the identifier that
+// took it are returned then.
+func (o cliNameOwners) claim(names []string, owner string) (string, string, bool) {
+	for _, name := range names {
+		if other, ok := o[name]; ok {
+			return name, other, false
+		}
+	}
+	for _, name := range names {
+		o[name] = owner
+	}
+	return "", "", true
+}
+
+// dropClashingCommands removes the functions whose command names are taken
+// by a function that precedes them.
+func dropClashingCommands(pkg *parser.PackageFunctions, opts *Options) *parser.PackageFunctions {
+	owners := make(cliNameOwners)
+	commands := *pkg
+	commands.Functions = make([]*parser.PkgFunc, 0, len(pkg.Functions))
+	for _, fn := range pkg.Functions {
+		if name, owner, ok := owners.claim(opts.cliNames(fn.Name), fn.Name); !ok {
+			log.Printf("[WARN]: skipping function %s: its command name %s clashes with the function %s", fn.Name, name, owner)
+			continue
+		}
+		commands.Functions = append(commands.Functions, fn)
+	}
+	return &commands
+}
+
+// toEnvName converts a name to the upper snake case of the environment
+// variables, e.g. maxRetries becomes MAX_RETRIES and my-app becomes MY_APP.
+func toEnvName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(toKebabCase(name), "-", "_"))
+}
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToKebabCase(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "maxRetries",
+			expected: "max-retries",
+		},
+		{
+			in:       "PrintStringMaybeUpper",
+			expected: "print-string-maybe-upper",
+		},
+		{
+			in:       "n",
+			expected: "n",
+		},
+		{
+			in:       "n64p",
+			expected: "n64p",
+		},
+		{
+			in:       "c128P",
+			expected: "c128-p",
+		},
+		{
+			in:       "HTTPServer",
+			expected: "http-server",
+		},
+		{
+			in:       "serverURL",
+			expected: "server-url",
+		},
+		{
+			in:       "dry_run",
+			expected: "dry-run",
+		},
+		{
+			in:       "ID",
+			expected: "id",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := toKebabCase(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual name %s and expected name %s are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestToEnvName(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "maxRetries",
+			expected: "MAX_RETRIES",
+		},
+		{
+			in:       "PrintStringMaybeUpper",
+			expected: "PRINT_STRING_MAYBE_UPPER",
+		},
+		{
+			in:       "my-app",
+			expected: "MY_APP",
+		},
+		{
+			in:       "MYAPP",
+			expected: "MYAPP",
+		},
+		{
+			in:       "dry_run",
+			expected: "DRY_RUN",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := toEnvName(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual name %s and expected name %s are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
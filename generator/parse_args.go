@@ -1,46 +1,806 @@
 package generator
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-type gosif_ReadFlag struct {
-	PassedFlag string
-	Args       []string
+// gosif_ArgsSpec describes the arguments that a function accepts on the
+// command line.
+type gosif_ArgsSpec struct {
+	// Flags maps the accepted flag names to the parameters names
+	Flags map[string]string
+	// Positional lists the flag names of the parameters that are bound to the
+	// positional arguments, in order
+	Positional []string
+	// LeadingPositional allows to pass the positional arguments before the
+	// first flag, otherwise they are only accepted after "--"
+	LeadingPositional bool
+	// CollectRest binds all the remaining positional arguments to the last
+	// positional parameter
+	CollectRest bool
+	// SliceFlags are the names of the parameters that take several
+	// arguments, an empty value assigned to them with "=" means no arguments
+	SliceFlags map[string]bool
+	// BoolFlags are the names of the parameters that can be set without an
+	// argument, they can be clustered after a single dash (e.g. -xvf)
+	BoolFlags map[string]bool
+	// CountFlags are the names of the parameters whose value is the number of
+	// times their flag is repeated (e.g. -vvv)
+	CountFlags map[string]bool
+	// LastWinsFlags are the names of the scalar parameters that take the
+	// value of the last occurrence of their flag, other scalar parameters
+	// cannot be repeated
+	LastWinsFlags map[string]bool
+	// Negations maps the negated names of the boolean flags (e.g. no-verbose)
+	// to the parameters names
+	Negations map[string]string
+	// Env maps the parameters names to the environment variables that are
+	// read if the flag is not passed
+	Env map[string]string
+	// Choices maps the parameters names to the values they accept
+	Choices map[string][]string
+	// Commands are the names of the function, its section of the
+	// configuration file is looked up by them
+	Commands []string
+	// Config is the path to the configuration file whose values are used
+	// for the flags that are neither passed nor set in the environment
+	Config string
 }
 
-func gosif_ReadArgs(args []string, funcFlags map[string]struct{}) (map[string]gosif_ReadFlag, error) {
-	if len(funcFlags) == 0 {
+func gosif_ReadArgs(args []string, spec *gosif_ArgsSpec) (map[string]gosif_ReadFlag, error) {
+	if spec == nil || len(spec.Flags) == 0 {
 		return nil, fmt.Errorf("internal error: function flags map cannot be empty")
 	}
 	parsedFlags := make(map[string]gosif_ReadFlag)
+	counts := make(map[string]int)
+	positional := make([]string, 0)
 	curPos := 0
+	if spec.LeadingPositional {
+		for curPos < len(args) && !gosif_UtilIsFlag(args[curPos]) {
+			positional = append(positional, gosif_UtilExtractArg(args[curPos]))
+			curPos++
+		}
+	}
 	for curPos < len(args) {
 		f := args[curPos]
 		curPos++
 		extractedFlag, err := gosif_UtilExtractFlag(f)
 		if err != nil {
-			return nil, fmt.Errorf("an error occurred during the flag \"%s\" extraction: %v", f, err)
+			return nil, fmt.Errorf("an error occurred during the flag \"%s\" extraction: %w", f, err)
 		}
 		if extractedFlag == "--" {
-			return nil, fmt.Errorf("\"%s\" found, but ending command options and passing positional arguments is NYI", extractedFlag)
+			positional = append(positional, args[curPos:]...)
+			break
 		}
-		if _, ok := funcFlags[extractedFlag]; !ok {
-			return nil, fmt.Errorf("an unexpected flag \"%s\" found", f)
+		extractedFlag, assignedVal, isAssigned := gosif_UtilSplitAssignment(extractedFlag)
+		if isAssigned {
+			f = f[:len(f)-len(assignedVal)-1]
+		}
+		if flagName, ok := spec.Negations[extractedFlag]; ok {
+			if isAssigned {
+				return nil, fmt.Errorf("the flag %s does not take a value", f)
+			}
+			readFlag := gosif_ReadFlag{
+				PassedFlag: f,
+				Args:       []string{"false"},
+			}
+			if err := gosif_UtilStoreFlag(parsedFlags, flagName, readFlag, spec); err != nil {
+				return nil, err
+			}
+			continue
 		}
-		flagArgs, err := gosif_UtilReadFlagArgs(args[curPos:], funcFlags)
+		passedFlags, flagNames, err := gosif_UtilResolveFlags(f, extractedFlag, spec)
 		if err != nil {
-			return nil, fmt.Errorf("an error occurred while parsing the flag %s arguments: %v", f, err)
+			return nil, err
 		}
-		curPos += len(flagArgs)
-		parsedFlags[extractedFlag] = gosif_ReadFlag{
-			PassedFlag: f,
-			Args:       flagArgs,
+		for i, flagName := range flagNames {
+			isLast := i == len(flagNames)-1
+			if spec.CountFlags[flagName] && !(isLast && isAssigned) {
+				counts[flagName]++
+				parsedFlags[flagName] = gosif_ReadFlag{
+					PassedFlag: passedFlags[i],
+					Args:       []string{strconv.Itoa(counts[flagName])},
+				}
+				continue
+			}
+			flagArgs := []string{}
+			if isLast && isAssigned {
+				flagArgs = gosif_UtilAssignedArgs(assignedVal, spec.SliceFlags[flagName])
+			} else if isLast {
+				flagArgs, err = gosif_UtilReadFlagArgs(args[curPos:], spec.Flags)
+				if err != nil {
+					return nil, fmt.Errorf("an error occurred while parsing the flag %s arguments: %w", passedFlags[i], err)
+				}
+				curPos += len(flagArgs)
+			}
+			readFlag := gosif_ReadFlag{
+				PassedFlag: passedFlags[i],
+				Args:       flagArgs,
+			}
+			if err := gosif_UtilStoreFlag(parsedFlags, flagName, readFlag, spec); err != nil {
+				return nil, err
+			}
 		}
 	}
+	if err := gosif_UtilBindPositional(positional, spec, parsedFlags); err != nil {
+		return nil, err
+	}
+	gosif_UtilReadEnv(spec, parsedFlags)
+	if err := gosif_UtilReadConfig(spec, parsedFlags); err != nil {
+		return nil, err
+	}
+	if err := gosif_UtilCheckChoices(spec, parsedFlags); err != nil {
+		return nil, err
+	}
 	return parsedFlags, nil
 }
 
+// gosif_UtilCheckChoices checks that the flags with the enumerated values
+// are set to one of them.
+func gosif_UtilCheckChoices(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	flagNames := make([]string, 0, len(spec.Choices))
+	for flagName := range spec.Choices {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+	for _, flagName := range flagNames {
+		readFlag, ok := parsedFlags[flagName]
+		if !ok {
+			continue
+		}
+		choices := spec.Choices[flagName]
+		if arg, ok := gosif_UtilFindUnexpected(readFlag.Args, choices); ok {
+			return fmt.Errorf("flag %s: unexpected value \"%s\", expected one of [%s]", readFlag.PassedFlag, arg, strings.Join(choices, ", "))
+		}
+	}
+	return nil
+}
+
+// gosif_UtilFindUnexpected returns the first argument that is not one of the
+// choices, any argument is expected if there are no choices.
+func gosif_UtilFindUnexpected(args []string, choices []string) (string, bool) {
+	if len(choices) == 0 {
+		return "", false
+	}
+	for _, arg := range args {
+		found := false
+		for _, choice := range choices {
+			if arg == choice {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// gosif_UtilReadEnv reads the environment variables of the flags that were
+// not passed on the command line.
+func gosif_UtilReadEnv(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) {
+	for flagName, envVar := range spec.Env {
+		if _, ok := parsedFlags[flagName]; ok {
+			continue
+		}
+		val, ok := os.LookupEnv(envVar)
+		if !ok {
+			continue
+		}
+		parsedFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: "$" + envVar,
+			Args:       gosif_UtilAssignedArgs(val, spec.SliceFlags[flagName]),
+		}
+	}
+}
+
+// gosif_UtilExtractConfigPath removes the --config option passed before the
+// function name from the command line arguments and returns its path.
+func gosif_UtilExtractConfigPath(args []string) ([]string, string, error) {
+	if len(args) < 2 {
+		return args, "", nil
+	}
+	if strings.HasPrefix(args[1], "--config=") {
+		path := strings.TrimPrefix(args[1], "--config=")
+		if len(path) == 0 {
+			return nil, "", fmt.Errorf("the option --config expects a file path")
+		}
+		return append([]string{args[0]}, args[2:]...), path, nil
+	}
+	if args[1] != "--config" {
+		return args, "", nil
+	}
+	if len(args) < 3 || len(args[2]) == 0 {
+		return nil, "", fmt.Errorf("the option --config expects a file path")
+	}
+	return append([]string{args[0]}, args[3:]...), args[2], nil
+}
+
+// gosif_Prompt describes a missing required flag whose value is asked for in
+// the interactive mode.
+type gosif_Prompt struct {
+	// Name is the name of the flag on the command line and Param is the
+	// name of the function parameter, they differ in the kebab-case mode
+	Name        string
+	Param       string
+	PassedFlag  string
+	Type        string
+	Description string
+	Choices     []string
+	IsSlice     bool
+}
+
+// gosif_UtilPrompt asks for the value of the flag until a non-empty value is
+// entered, the arguments of the slice flags are separated with spaces.
+func gosif_UtilPrompt(in *bufio.Reader, out io.Writer, p gosif_Prompt) (gosif_ReadFlag, error) {
+	for {
+		fmt.Fprintf(out, "%s (%s)", p.PassedFlag, p.Type)
+		if len(p.Choices) != 0 {
+			fmt.Fprintf(out, " {%s}", strings.Join(p.Choices, "|"))
+		}
+		if len(p.Description) != 0 {
+			fmt.Fprintf(out, " %s", p.Description)
+		}
+		fmt.Fprint(out, ": ")
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				fmt.Fprintln(out)
+				return gosif_ReadFlag{}, gosif_UtilMissingFlag(p.Name)
+			}
+			fmt.Fprintln(out, "a value is required")
+			continue
+		}
+		args := []string{line}
+		if p.IsSlice {
+			args = strings.Fields(line)
+		}
+		if unexpected, ok := gosif_UtilFindUnexpected(args, p.Choices); ok {
+			fmt.Fprintf(out, "unexpected value \"%s\", expected one of [%s]\n", unexpected, strings.Join(p.Choices, ", "))
+			continue
+		}
+		return gosif_ReadFlag{
+			PassedFlag: p.PassedFlag,
+			Args:       args,
+		}, nil
+	}
+}
+
+// gosif_Config holds the values read from a configuration file: the values
+// set outside of any section apply to every function, the ones set in a
+// section named after a function override them.
+type gosif_Config struct {
+	Keys     map[string][]string
+	Sections map[string]map[string][]string
+}
+
+// gosif_ConfigSections are the names of all the functions, a section of the
+// configuration file has to be named after one of them.
+var gosif_ConfigSections map[string]bool
+
+// gosif_ConfigKeys are the flags names of all the functions, a key set
+// outside of the sections has to name a flag of one of them.
+var gosif_ConfigKeys map[string]bool
+
+// gosif_UtilReadConfig reads the configuration file values of the flags
+// that were neither passed on the command line nor set in the environment.
+func gosif_UtilReadConfig(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	if len(spec.Config) == 0 {
+		return nil
+	}
+	config, err := gosif_LoadConfig(spec.Config)
+	if err != nil {
+		return err
+	}
+	sections := make([]string, 0, len(config.Sections))
+	for section := range config.Sections {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		if !gosif_ConfigSections[section] {
+			return fmt.Errorf("config file %s: unknown section \"%s\", expected a function name", spec.Config, section)
+		}
+	}
+	configFlags, err := gosif_UtilConfigFlags(spec, config.Keys, "")
+	if err != nil {
+		return err
+	}
+	for _, command := range spec.Commands {
+		section, ok := config.Sections[command]
+		if !ok {
+			continue
+		}
+		sectionFlags, err := gosif_UtilConfigFlags(spec, section, command+".")
+		if err != nil {
+			return err
+		}
+		for flagName, readFlag := range sectionFlags {
+			configFlags[flagName] = readFlag
+		}
+	}
+	for flagName, readFlag := range configFlags {
+		if _, ok := parsedFlags[flagName]; !ok {
+			parsedFlags[flagName] = readFlag
+		}
+	}
+	return nil
+}
+
+// gosif_UtilConfigFlags maps the keys of a configuration file section to the
+// flags, the keys are reported prefixed with the section name. The keys set
+// outside of the sections that name a flag of another function are skipped.
+func gosif_UtilConfigFlags(spec *gosif_ArgsSpec, values map[string][]string, keyPrefix string) (map[string]gosif_ReadFlag, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	configFlags := make(map[string]gosif_ReadFlag)
+	setBy := make(map[string]string)
+	for _, key := range keys {
+		reportedKey := keyPrefix + key
+		flagName, ok := spec.Flags[key]
+		if !ok && len(keyPrefix) == 0 && gosif_ConfigKeys[key] {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("config file %s: unknown key \"%s\"", spec.Config, reportedKey)
+		}
+		if prevKey, ok := setBy[flagName]; ok {
+			return nil, fmt.Errorf("config file %s: the keys \"%s\" and \"%s\" set the same flag", spec.Config, prevKey, reportedKey)
+		}
+		setBy[flagName] = reportedKey
+		args := values[key]
+		if spec.SliceFlags[flagName] && len(args) == 1 {
+			args = gosif_UtilAssignedArgs(args[0], true)
+		} else if !spec.SliceFlags[flagName] && len(args) != 1 {
+			return nil, fmt.Errorf("config file %s: the key \"%s\" expects a single value, got %d", spec.Config, reportedKey, len(args))
+		}
+		configFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: spec.Config + ":" + reportedKey,
+			Args:       args,
+			Source:     fmt.Sprintf("config file %s, key \"%s\"", spec.Config, reportedKey),
+		}
+	}
+	return configFlags, nil
+}
+
+// gosif_LoadConfig reads a configuration file, the files with the .json
+// extension are parsed as JSON, the other ones as INI files.
+func gosif_LoadConfig(path string) (*gosif_Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		return gosif_UtilParseJSONConfig(path, content)
+	}
+	return gosif_UtilParseINIConfig(path, string(content))
+}
+
+// gosif_UtilParseJSONConfig parses a JSON object whose nested objects are
+// the functions sections, a value can be a string, a number, a boolean or
+// an array of those, a null value is ignored.
+func gosif_UtilParseJSONConfig(path string, content []byte) (*gosif_Config, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	config := &gosif_Config{
+		Keys:     make(map[string][]string),
+		Sections: make(map[string]map[string][]string),
+	}
+	for key, val := range raw {
+		section, ok := val.(map[string]interface{})
+		if !ok {
+			if err := gosif_UtilAddJSONValue(config.Keys, key, val); err != nil {
+				return nil, fmt.Errorf("config file %s: key \"%s\": %w", path, key, err)
+			}
+			continue
+		}
+		config.Sections[key] = make(map[string][]string)
+		for sectionKey, sectionVal := range section {
+			if err := gosif_UtilAddJSONValue(config.Sections[key], sectionKey, sectionVal); err != nil {
+				return nil, fmt.Errorf("config file %s: key \"%s.%s\": %w", path, key, sectionKey, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+func gosif_UtilAddJSONValue(values map[string][]string, key string, val interface{}) error {
+	if val == nil {
+		return nil
+	}
+	elems, isArray := val.([]interface{})
+	if !isArray {
+		elems = []interface{}{val}
+	}
+	args := make([]string, len(elems))
+	for i, elem := range elems {
+		switch v := elem.(type) {
+		case string:
+			args[i] = v
+		case json.Number:
+			args[i] = v.String()
+		case bool:
+			args[i] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("unexpected value %v, expected a string, a number, a boolean or an array of those", elem)
+		}
+	}
+	values[key] = args
+	return nil
+}
+
+// gosif_UtilParseINIConfig parses the "key = value" lines of an INI file, the
+// "[Function]" lines start the functions sections and the lines starting
+// with "#" or ";" are comments.
+func gosif_UtilParseINIConfig(path string, content string) (*gosif_Config, error) {
+	config := &gosif_Config{
+		Keys:     make(map[string][]string),
+		Sections: make(map[string]map[string][]string),
+	}
+	values := config.Keys
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := config.Sections[section]; !ok {
+				config.Sections[section] = make(map[string][]string)
+			}
+			values = config.Sections[section]
+			continue
+		}
+		sepIdx := strings.Index(line, "=")
+		if sepIdx <= 0 {
+			return nil, fmt.Errorf("config file %s, line %d: expected \"key = value\" or \"[section]\", got \"%s\"", path, i+1, line)
+		}
+		key := strings.TrimSpace(line[:sepIdx])
+		val := strings.TrimSpace(line[sepIdx+1:])
+		if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("config file %s, line %d: %w", path, i+1, err)
+			}
+			val = unquoted
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("config file %s, line %d: the key \"%s\" is set more than once", path, i+1, key)
+		}
+		values[key] = []string{val}
+	}
+	return config, nil
+}
+
+// gosif_UnknownFlagError is returned when a passed flag does not match any
+// of the function flags.
+type gosif_UnknownFlagError struct {
+	// Flag is the flag as it was passed, e.g. --nme
+	Flag string
+	// Suggestion is the closest flag name if there is one, e.g. --name
+	Suggestion string
+	// Candidates are the flags that an ambiguous prefix may refer to
+	Candidates []string
+}
+
+func (e *gosif_UnknownFlagError) Error() string {
+	if len(e.Candidates) != 0 {
+		return fmt.Sprintf("an ambiguous flag \"%s\" found, it may refer to %s", e.Flag, strings.Join(e.Candidates, ", "))
+	}
+	if len(e.Suggestion) == 0 {
+		return fmt.Sprintf("an unexpected flag \"%s\" found", e.Flag)
+	}
+	return fmt.Sprintf("an unexpected flag \"%s\" found, did you mean %s?", e.Flag, e.Suggestion)
+}
+
+// gosif_MissingFlagError is returned when a required flag was not passed.
+type gosif_MissingFlagError struct {
+	// Flag is the long form of the flag, e.g. --name
+	Flag string
+}
+
+func (e *gosif_MissingFlagError) Error() string {
+	return fmt.Sprintf("a required flag \"%s\" was not passed", e.Flag)
+}
+
+// gosif_UtilMissingFlag returns the error reporting that the required flag
+// name was not passed.
+func gosif_UtilMissingFlag(name string) error {
+	return &gosif_MissingFlagError{Flag: "--" + name}
+}
+
+// gosif_CastError is returned when an argument of a flag cannot be cast to
+// the flag type, it wraps the error of the cast function.
+type gosif_CastError struct {
+	Flag  string
+	Value string
+	Type  string
+	// Source is where the argument was read from if it was not passed on
+	// the command line
+	Source string
+	Err    error
+}
+
+func (e *gosif_CastError) Error() string {
+	if len(e.Source) == 0 {
+		return fmt.Sprintf("cast failed: %v", e.Err)
+	}
+	return fmt.Sprintf("cast failed: %s: %v", e.Source, e.Err)
+}
+
+func (e *gosif_CastError) Unwrap() error {
+	return e.Err
+}
+
+// gosif_ArityError is returned when a flag is passed with a wrong number of
+// arguments, when a flag that takes a single value is repeated, or when too
+// many positional arguments are passed.
+type gosif_ArityError struct {
+	// Flag is the flag as it was passed, it is empty for the positional
+	// arguments
+	Flag string
+	// Expected is the number of the arguments that the flag takes, or the
+	// maximal number of them if AtMost is set
+	Expected int
+	AtMost   bool
+	Args     []string
+	// Previous is the flag as it was passed the first time if it is
+	// repeated
+	Previous string
+}
+
+func (e *gosif_ArityError) Error() string {
+	switch {
+	case len(e.Previous) != 0:
+		return fmt.Sprintf("the flag %s was passed more than once (previously as %s)", e.Flag, e.Previous)
+	case len(e.Flag) == 0 && e.Expected == 0:
+		return fmt.Sprintf("unexpected positional arguments %v: the function does not accept positional arguments", e.Args)
+	case len(e.Flag) == 0:
+		return fmt.Sprintf("too many positional arguments: expected at most %d, got %d (%v)", e.Expected, len(e.Args), e.Args)
+	}
+	expected := fmt.Sprintf("%d argument", e.Expected)
+	if e.Expected != 1 {
+		expected += "s"
+	}
+	if e.AtMost {
+		expected = "at most " + expected
+	}
+	return fmt.Sprintf("flag %s: expected %s, but got %d (%v)", e.Flag, expected, len(e.Args), e.Args)
+}
+
+// gosif_UtilCastError reports the source of the arguments that failed to be
+// cast unless they were passed on the command line.
+func gosif_UtilCastError(parsedFlag gosif_ReadFlag, arg string, typ string, err error) error {
+	return &gosif_CastError{
+		Flag:   parsedFlag.PassedFlag,
+		Value:  arg,
+		Type:   typ,
+		Source: parsedFlag.Source,
+		Err:    err,
+	}
+}
+
+// gosif_UtilStoreFlag stores the read flag, the arguments of a repeated
+// slice flag are appended to the previous ones.
+func gosif_UtilStoreFlag(parsedFlags map[string]gosif_ReadFlag, flagName string, readFlag gosif_ReadFlag, spec *gosif_ArgsSpec) error {
+	prev, ok := parsedFlags[flagName]
+	if !ok || spec.LastWinsFlags[flagName] {
+		parsedFlags[flagName] = readFlag
+		return nil
+	}
+	if !spec.SliceFlags[flagName] {
+		return &gosif_ArityError{Flag: readFlag.PassedFlag, Args: readFlag.Args, Previous: prev.PassedFlag}
+	}
+	parsedFlags[flagName] = gosif_ReadFlag{
+		PassedFlag: readFlag.PassedFlag,
+		Args:       append(prev.Args, readFlag.Args...),
+	}
+	return nil
+}
+
+// gosif_UtilResolveFlags returns the parameters that the flag refers to
+// along with the flags as they should be reported: a single parameter, or
+// several for a cluster of single-letter flags (e.g. -xvf).
+func gosif_UtilResolveFlags(passedFlag string, extracted string, spec *gosif_ArgsSpec) ([]string, []string, error) {
+	if flagName, ok := spec.Flags[extracted]; ok {
+		return []string{passedFlag}, []string{flagName}, nil
+	}
+	if passedFlags, flagNames, ok := gosif_UtilSplitCluster(passedFlag, extracted, spec); ok {
+		return passedFlags, flagNames, nil
+	}
+	flagName, err := gosif_UtilResolveFlag(passedFlag, extracted, spec.Flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{passedFlag}, []string{flagName}, nil
+}
+
+// gosif_UtilSplitCluster splits a single-dash cluster of single-letter flags,
+// all the flags except the last one must be boolean flags or counters.
+func gosif_UtilSplitCluster(passedFlag string, extracted string, spec *gosif_ArgsSpec) ([]string, []string, bool) {
+	letters := []rune(extracted)
+	if strings.HasPrefix(passedFlag, "--") || len(letters) < 2 {
+		return nil, nil, false
+	}
+	passedFlags := make([]string, len(letters))
+	flagNames := make([]string, len(letters))
+	for i, l := range letters {
+		flagName, ok := spec.Flags[string(l)]
+		if !ok {
+			return nil, nil, false
+		}
+		if i != len(letters)-1 && !spec.BoolFlags[flagName] && !spec.CountFlags[flagName] {
+			return nil, nil, false
+		}
+		passedFlags[i] = "-" + string(l)
+		flagNames[i] = flagName
+	}
+	return passedFlags, flagNames, true
+}
+
+// gosif_UtilResolveFlag returns the name of the parameter that the flag
+// refers to, the flag is either one of the accepted names or an unambiguous
+// prefix of them.
+func gosif_UtilResolveFlag(passedFlag string, extracted string, funcFlags map[string]string) (string, error) {
+	if flagName, ok := funcFlags[extracted]; ok {
+		return flagName, nil
+	}
+	candidates := gosif_UtilMatchFlagPrefix(extracted, funcFlags)
+	if len(candidates) == 0 {
+		aliases := make([]string, 0, len(funcFlags))
+		for alias := range funcFlags {
+			aliases = append(aliases, alias)
+		}
+		if suggestion, ok := gosif_UtilSuggest(extracted, aliases); ok {
+			dashes := "--"
+			if len([]rune(suggestion)) == 1 {
+				dashes = "-"
+			}
+			return "", &gosif_UnknownFlagError{Flag: passedFlag, Suggestion: dashes + suggestion}
+		}
+		return "", &gosif_UnknownFlagError{Flag: passedFlag}
+	}
+	if len(candidates) > 1 {
+		dashes := passedFlag[:len(passedFlag)-len(extracted)]
+		names := make([]string, 0, len(candidates))
+		for alias := range candidates {
+			names = append(names, dashes+alias)
+		}
+		sort.Strings(names)
+		return "", &gosif_UnknownFlagError{Flag: passedFlag, Candidates: names}
+	}
+	for _, flagName := range candidates {
+		return flagName, nil
+	}
+	return "", nil
+}
+
+// gosif_UtilMatchFlagPrefix returns the flags names that start with the prefix
+// mapped to the parameters names, the flags that refer to the same parameter
+// are reported once under the longest (i.e. the full) name.
+func gosif_UtilMatchFlagPrefix(prefix string, funcFlags map[string]string) map[string]string {
+	byParam := make(map[string]string)
+	for alias, flagName := range funcFlags {
+		if !strings.HasPrefix(alias, prefix) {
+			continue
+		}
+		prev, ok := byParam[flagName]
+		if !ok || len(alias) > len(prev) || (len(alias) == len(prev) && alias < prev) {
+			byParam[flagName] = alias
+		}
+	}
+	candidates := make(map[string]string, len(byParam))
+	for flagName, alias := range byParam {
+		candidates[alias] = flagName
+	}
+	return candidates
+}
+
+// gosif_UtilSplitAssignment splits the "flag=value" form of the extracted
+// flag into the flag name and the assigned value.
+func gosif_UtilSplitAssignment(extracted string) (string, string, bool) {
+	eqPos := strings.IndexByte(extracted, '=')
+	if eqPos <= 0 {
+		return extracted, "", false
+	}
+	return extracted[:eqPos], extracted[eqPos+1:], true
+}
+
+func gosif_UtilAssignedArgs(val string, isSlice bool) []string {
+	if isSlice && len(val) == 0 {
+		return []string{}
+	}
+	return []string{val}
+}
+
+// gosif_SplitArgs splits each of the arguments around the separator, a
+// backslash escapes the separator and itself.
+func gosif_SplitArgs(args []string, sep string) []string {
+	splitArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		var sb strings.Builder
+		for i := 0; i < len(arg); {
+			if arg[i] == '\\' && i+1 < len(arg) {
+				if strings.HasPrefix(arg[i+1:], sep) {
+					sb.WriteString(sep)
+					i += 1 + len(sep)
+					continue
+				}
+				if arg[i+1] == '\\' {
+					sb.WriteByte('\\')
+					i += 2
+					continue
+				}
+			}
+			if strings.HasPrefix(arg[i:], sep) {
+				splitArgs = append(splitArgs, sb.String())
+				sb.Reset()
+				i += len(sep)
+				continue
+			}
+			sb.WriteByte(arg[i])
+			i++
+		}
+		splitArgs = append(splitArgs, sb.String())
+	}
+	return splitArgs
+}
+
+func gosif_UtilIsFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+// gosif_UtilBindPositional binds the positional arguments to the positional
+// parameters in order.
+func gosif_UtilBindPositional(positional []string, spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	if len(positional) == 0 {
+		return nil
+	}
+	if len(spec.Positional) == 0 {
+		return &gosif_ArityError{Args: positional}
+	}
+	if len(positional) > len(spec.Positional) && !spec.CollectRest {
+		return &gosif_ArityError{Expected: len(spec.Positional), AtMost: true, Args: positional}
+	}
+	for i, arg := range positional {
+		posIdx := i
+		if posIdx >= len(spec.Positional) {
+			posIdx = len(spec.Positional) - 1
+		}
+		posName := spec.Positional[posIdx]
+		flagName, ok := spec.Flags[posName]
+		if !ok {
+			return fmt.Errorf("internal error: the positional parameter %s is not a known flag", posName)
+		}
+		passedName := fmt.Sprintf("<%s>", posName)
+		parsedFlag, ok := parsedFlags[flagName]
+		if ok && parsedFlag.PassedFlag != passedName {
+			return fmt.Errorf("the argument %s was passed both with the flag %s and as the positional argument \"%s\"", passedName, parsedFlag.PassedFlag, arg)
+		}
+		parsedFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: passedName,
+			Args:       append(parsedFlag.Args, arg),
+		}
+	}
+	return nil
+}
+
 func gosif_UtilExtractFlag(f string) (string, error) {
 	if len(f) == 0 {
 		return "", fmt.Errorf("internal error: expected a flag, got an empty string")
@@ -68,7 +828,7 @@ func gosif_UtilExtractFlagAfterDash(f string) string {
 	return f[2:]
 }
 
-func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]struct{}) ([]string, error) {
+func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]string) ([]string, error) {
 	flagArgs := make([]string, 0)
 	for _, a := range args {
 		if a[0] == '-' {
@@ -76,9 +836,23 @@ func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]struct{}) ([]str
 			if extracted == "--" {
 				break
 			}
+			extracted, _, _ = gosif_UtilSplitAssignment(extracted)
 			if _, ok := funcFlags[extracted]; ok {
 				break
 			}
+			if _, ok := funcFlags[strings.TrimPrefix(extracted, "no-")]; ok {
+				// a negated boolean flag
+				break
+			}
+			if len(extracted) != 0 && len(gosif_UtilMatchFlagPrefix(extracted, funcFlags)) != 0 {
+				break
+			}
+			if len(extracted) != 0 && a[1] != '-' {
+				// a possible cluster of single-letter flags
+				if _, ok := funcFlags[string([]rune(extracted)[0])]; ok {
+					break
+				}
+			}
 		}
 		extractedArg := gosif_UtilExtractArg(a)
 		if len(extractedArg) == 0 {
@@ -113,66 +887,816 @@ func gosif_UtilExtractArg(arg string) string {
 
 // TODO: we need this function only if there are flags with a single argument
 // We should filter it out in other cases
-func gosif_GetFlagArg(args []string) (string, error) {
-	if len(args) == 0 {
-		return "", fmt.Errorf("no arguments passed")
-	}
-	if len(args) > 1 {
-		return "", fmt.Errorf("expected a single argument, got %d (%v)", len(args), args)
+func gosif_GetFlagArg(parsedFlag gosif_ReadFlag) (string, error) {
+	if len(parsedFlag.Args) != 1 {
+		return "", &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: 1, Args: parsedFlag.Args}
 	}
-	return args[0], nil
+	return parsedFlag.Args[0], nil
 }
 
 // TODO: we need this function only if there are flags with a single boolean argument
 // We should filter it out in other cases
-func gosif_GetBoolArg(args []string) (string, error) {
-	if len(args) == 0 {
+func gosif_GetBoolArg(parsedFlag gosif_ReadFlag) (string, error) {
+	if len(parsedFlag.Args) == 0 {
 		return "true", nil
 	}
-	if len(args) > 1 {
-		return "", fmt.Errorf("expected a single argument, got %d (%v)", len(args), args)
+	if len(parsedFlag.Args) > 1 {
+		return "", &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: 1, AtMost: true, Args: parsedFlag.Args}
 	}
-	return args[0], nil
+	return parsedFlag.Args[0], nil
 }
 
 const gosifFuncs = `
-type gosif_ReadFlag struct {
-	PassedFlag string
-	Args       []string
+// gosif_ArgsSpec describes the arguments that a function accepts on the
+// command line.
+type gosif_ArgsSpec struct {
+	// Flags maps the accepted flag names to the parameters names
+	Flags map[string]string
+	// Positional lists the flag names of the parameters that are bound to the
+	// positional arguments, in order
+	Positional []string
+	// LeadingPositional allows to pass the positional arguments before the
+	// first flag, otherwise they are only accepted after "--"
+	LeadingPositional bool
+	// CollectRest binds all the remaining positional arguments to the last
+	// positional parameter
+	CollectRest bool
+	// SliceFlags are the names of the parameters that take several
+	// arguments, an empty value assigned to them with "=" means no arguments
+	SliceFlags map[string]bool
+	// BoolFlags are the names of the parameters that can be set without an
+	// argument, they can be clustered after a single dash (e.g. -xvf)
+	BoolFlags map[string]bool
+	// CountFlags are the names of the parameters whose value is the number of
+	// times their flag is repeated (e.g. -vvv)
+	CountFlags map[string]bool
+	// LastWinsFlags are the names of the scalar parameters that take the
+	// value of the last occurrence of their flag, other scalar parameters
+	// cannot be repeated
+	LastWinsFlags map[string]bool
+	// Negations maps the negated names of the boolean flags (e.g. no-verbose)
+	// to the parameters names
+	Negations map[string]string
+	// Env maps the parameters names to the environment variables that are
+	// read if the flag is not passed
+	Env map[string]string
+	// Choices maps the parameters names to the values they accept
+	Choices map[string][]string
+	// Commands are the names of the function, its section of the
+	// configuration file is looked up by them
+	Commands []string
+	// Config is the path to the configuration file whose values are used
+	// for the flags that are neither passed nor set in the environment
+	Config string
 }
 
-func gosif_ReadArgs(args []string, funcFlags map[string]struct{}) (map[string]gosif_ReadFlag, error) {
-	if len(funcFlags) == 0 {
+func gosif_ReadArgs(args []string, spec *gosif_ArgsSpec) (map[string]gosif_ReadFlag, error) {
+	if spec == nil || len(spec.Flags) == 0 {
 		return nil, fmt.Errorf("internal error: function flags map cannot be empty")
 	}
 	parsedFlags := make(map[string]gosif_ReadFlag)
+	counts := make(map[string]int)
+	positional := make([]string, 0)
 	curPos := 0
+	if spec.LeadingPositional {
+		for curPos < len(args) && !gosif_UtilIsFlag(args[curPos]) {
+			positional = append(positional, gosif_UtilExtractArg(args[curPos]))
+			curPos++
+		}
+	}
 	for curPos < len(args) {
 		f := args[curPos]
 		curPos++
 		extractedFlag, err := gosif_UtilExtractFlag(f)
 		if err != nil {
-			return nil, fmt.Errorf("an error occurred during the flag \"%s\" extraction: %v", f, err)
+			return nil, fmt.Errorf("an error occurred during the flag \"%s\" extraction: %w", f, err)
 		}
 		if extractedFlag == "--" {
-			return nil, fmt.Errorf("\"%s\" found, but ending command options and passing positional arguments is NYI", extractedFlag)
+			positional = append(positional, args[curPos:]...)
+			break
 		}
-		if _, ok := funcFlags[extractedFlag]; !ok {
-			return nil, fmt.Errorf("an unexpected flag \"%s\" found", f)
+		extractedFlag, assignedVal, isAssigned := gosif_UtilSplitAssignment(extractedFlag)
+		if isAssigned {
+			f = f[:len(f)-len(assignedVal)-1]
 		}
-		flagArgs, err := gosif_UtilReadFlagArgs(args[curPos:], funcFlags)
+		if flagName, ok := spec.Negations[extractedFlag]; ok {
+			if isAssigned {
+				return nil, fmt.Errorf("the flag %s does not take a value", f)
+			}
+			readFlag := gosif_ReadFlag{
+				PassedFlag: f,
+				Args:       []string{"false"},
+			}
+			if err := gosif_UtilStoreFlag(parsedFlags, flagName, readFlag, spec); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		passedFlags, flagNames, err := gosif_UtilResolveFlags(f, extractedFlag, spec)
 		if err != nil {
-			return nil, fmt.Errorf("an error occurred while parsing the flag %s arguments: %v", f, err)
+			return nil, err
 		}
-		curPos += len(flagArgs)
-		parsedFlags[extractedFlag] = gosif_ReadFlag{
-			PassedFlag: f,
-			Args:       flagArgs,
+		for i, flagName := range flagNames {
+			isLast := i == len(flagNames)-1
+			if spec.CountFlags[flagName] && !(isLast && isAssigned) {
+				counts[flagName]++
+				parsedFlags[flagName] = gosif_ReadFlag{
+					PassedFlag: passedFlags[i],
+					Args:       []string{strconv.Itoa(counts[flagName])},
+				}
+				continue
+			}
+			flagArgs := []string{}
+			if isLast && isAssigned {
+				flagArgs = gosif_UtilAssignedArgs(assignedVal, spec.SliceFlags[flagName])
+			} else if isLast {
+				flagArgs, err = gosif_UtilReadFlagArgs(args[curPos:], spec.Flags)
+				if err != nil {
+					return nil, fmt.Errorf("an error occurred while parsing the flag %s arguments: %w", passedFlags[i], err)
+				}
+				curPos += len(flagArgs)
+			}
+			readFlag := gosif_ReadFlag{
+				PassedFlag: passedFlags[i],
+				Args:       flagArgs,
+			}
+			if err := gosif_UtilStoreFlag(parsedFlags, flagName, readFlag, spec); err != nil {
+				return nil, err
+			}
 		}
 	}
+	if err := gosif_UtilBindPositional(positional, spec, parsedFlags); err != nil {
+		return nil, err
+	}
+	gosif_UtilReadEnv(spec, parsedFlags)
+	if err := gosif_UtilReadConfig(spec, parsedFlags); err != nil {
+		return nil, err
+	}
+	if err := gosif_UtilCheckChoices(spec, parsedFlags); err != nil {
+		return nil, err
+	}
 	return parsedFlags, nil
 }
 
+// gosif_UtilCheckChoices checks that the flags with the enumerated values
+// are set to one of them.
+func gosif_UtilCheckChoices(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	flagNames := make([]string, 0, len(spec.Choices))
+	for flagName := range spec.Choices {
+		flagNames = append(flagNames, flagName)
+	}
+	sort.Strings(flagNames)
+	for _, flagName := range flagNames {
+		readFlag, ok := parsedFlags[flagName]
+		if !ok {
+			continue
+		}
+		choices := spec.Choices[flagName]
+		if arg, ok := gosif_UtilFindUnexpected(readFlag.Args, choices); ok {
+			return fmt.Errorf("flag %s: unexpected value \"%s\", expected one of [%s]", readFlag.PassedFlag, arg, strings.Join(choices, ", "))
+		}
+	}
+	return nil
+}
+
+// gosif_UtilFindUnexpected returns the first argument that is not one of the
+// choices, any argument is expected if there are no choices.
+func gosif_UtilFindUnexpected(args []string, choices []string) (string, bool) {
+	if len(choices) == 0 {
+		return "", false
+	}
+	for _, arg := range args {
+		found := false
+		for _, choice := range choices {
+			if arg == choice {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// gosif_UtilReadEnv reads the environment variables of the flags that were
+// not passed on the command line.
+func gosif_UtilReadEnv(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) {
+	for flagName, envVar := range spec.Env {
+		if _, ok := parsedFlags[flagName]; ok {
+			continue
+		}
+		val, ok := os.LookupEnv(envVar)
+		if !ok {
+			continue
+		}
+		parsedFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: "$" + envVar,
+			Args:       gosif_UtilAssignedArgs(val, spec.SliceFlags[flagName]),
+		}
+	}
+}
+
+// gosif_UtilExtractConfigPath removes the --config option passed before the
+// function name from the command line arguments and returns its path.
+func gosif_UtilExtractConfigPath(args []string) ([]string, string, error) {
+	if len(args) < 2 {
+		return args, "", nil
+	}
+	if strings.HasPrefix(args[1], "--config=") {
+		path := strings.TrimPrefix(args[1], "--config=")
+		if len(path) == 0 {
+			return nil, "", fmt.Errorf("the option --config expects a file path")
+		}
+		return append([]string{args[0]}, args[2:]...), path, nil
+	}
+	if args[1] != "--config" {
+		return args, "", nil
+	}
+	if len(args) < 3 || len(args[2]) == 0 {
+		return nil, "", fmt.Errorf("the option --config expects a file path")
+	}
+	return append([]string{args[0]}, args[3:]...), args[2], nil
+}
+
+// gosif_Prompt describes a missing required flag whose value is asked for in
+// the interactive mode.
+type gosif_Prompt struct {
+	// Name is the name of the flag on the command line and Param is the
+	// name of the function parameter, they differ in the kebab-case mode
+	Name        string
+	Param       string
+	PassedFlag  string
+	Type        string
+	Description string
+	Choices     []string
+	IsSlice     bool
+}
+
+// gosif_UtilPrompt asks for the value of the flag until a non-empty value is
+// entered, the arguments of the slice flags are separated with spaces.
+func gosif_UtilPrompt(in *bufio.Reader, out io.Writer, p gosif_Prompt) (gosif_ReadFlag, error) {
+	for {
+		fmt.Fprintf(out, "%s (%s)", p.PassedFlag, p.Type)
+		if len(p.Choices) != 0 {
+			fmt.Fprintf(out, " {%s}", strings.Join(p.Choices, "|"))
+		}
+		if len(p.Description) != 0 {
+			fmt.Fprintf(out, " %s", p.Description)
+		}
+		fmt.Fprint(out, ": ")
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				fmt.Fprintln(out)
+				return gosif_ReadFlag{}, gosif_UtilMissingFlag(p.Name)
+			}
+			fmt.Fprintln(out, "a value is required")
+			continue
+		}
+		args := []string{line}
+		if p.IsSlice {
+			args = strings.Fields(line)
+		}
+		if unexpected, ok := gosif_UtilFindUnexpected(args, p.Choices); ok {
+			fmt.Fprintf(out, "unexpected value \"%s\", expected one of [%s]\n", unexpected, strings.Join(p.Choices, ", "))
+			continue
+		}
+		return gosif_ReadFlag{
+			PassedFlag: p.PassedFlag,
+			Args:       args,
+		}, nil
+	}
+}
+
+// gosif_Config holds the values read from a configuration file: the values
+// set outside of any section apply to every function, the ones set in a
+// section named after a function override them.
+type gosif_Config struct {
+	Keys     map[string][]string
+	Sections map[string]map[string][]string
+}
+
+// gosif_ConfigSections are the names of all the functions, a section of the
+// configuration file has to be named after one of them.
+var gosif_ConfigSections map[string]bool
+
+// gosif_ConfigKeys are the flags names of all the functions, a key set
+// outside of the sections has to name a flag of one of them.
+var gosif_ConfigKeys map[string]bool
+
+// gosif_UtilReadConfig reads the configuration file values of the flags
+// that were neither passed on the command line nor set in the environment.
+func gosif_UtilReadConfig(spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	if len(spec.Config) == 0 {
+		return nil
+	}
+	config, err := gosif_LoadConfig(spec.Config)
+	if err != nil {
+		return err
+	}
+	sections := make([]string, 0, len(config.Sections))
+	for section := range config.Sections {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		if !gosif_ConfigSections[section] {
+			return fmt.Errorf("config file %s: unknown section \"%s\", expected a function name", spec.Config, section)
+		}
+	}
+	configFlags, err := gosif_UtilConfigFlags(spec, config.Keys, "")
+	if err != nil {
+		return err
+	}
+	for _, command := range spec.Commands {
+		section, ok := config.Sections[command]
+		if !ok {
+			continue
+		}
+		sectionFlags, err := gosif_UtilConfigFlags(spec, section, command+".")
+		if err != nil {
+			return err
+		}
+		for flagName, readFlag := range sectionFlags {
+			configFlags[flagName] = readFlag
+		}
+	}
+	for flagName, readFlag := range configFlags {
+		if _, ok := parsedFlags[flagName]; !ok {
+			parsedFlags[flagName] = readFlag
+		}
+	}
+	return nil
+}
+
+// gosif_UtilConfigFlags maps the keys of a configuration file section to the
+// flags, the keys are reported prefixed with the section name. The keys set
+// outside of the sections that name a flag of another function are skipped.
+func gosif_UtilConfigFlags(spec *gosif_ArgsSpec, values map[string][]string, keyPrefix string) (map[string]gosif_ReadFlag, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	configFlags := make(map[string]gosif_ReadFlag)
+	setBy := make(map[string]string)
+	for _, key := range keys {
+		reportedKey := keyPrefix + key
+		flagName, ok := spec.Flags[key]
+		if !ok && len(keyPrefix) == 0 && gosif_ConfigKeys[key] {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("config file %s: unknown key \"%s\"", spec.Config, reportedKey)
+		}
+		if prevKey, ok := setBy[flagName]; ok {
+			return nil, fmt.Errorf("config file %s: the keys \"%s\" and \"%s\" set the same flag", spec.Config, prevKey, reportedKey)
+		}
+		setBy[flagName] = reportedKey
+		args := values[key]
+		if spec.SliceFlags[flagName] && len(args) == 1 {
+			args = gosif_UtilAssignedArgs(args[0], true)
+		} else if !spec.SliceFlags[flagName] && len(args) != 1 {
+			return nil, fmt.Errorf("config file %s: the key \"%s\" expects a single value, got %d", spec.Config, reportedKey, len(args))
+		}
+		configFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: spec.Config + ":" + reportedKey,
+			Args:       args,
+			Source:     fmt.Sprintf("config file %s, key \"%s\"", spec.Config, reportedKey),
+		}
+	}
+	return configFlags, nil
+}
+
+// gosif_LoadConfig reads a configuration file, the files with the .json
+// extension are parsed as JSON, the other ones as INI files.
+func gosif_LoadConfig(path string) (*gosif_Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		return gosif_UtilParseJSONConfig(path, content)
+	}
+	return gosif_UtilParseINIConfig(path, string(content))
+}
+
+// gosif_UtilParseJSONConfig parses a JSON object whose nested objects are
+// the functions sections, a value can be a string, a number, a boolean or
+// an array of those, a null value is ignored.
+func gosif_UtilParseJSONConfig(path string, content []byte) (*gosif_Config, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	config := &gosif_Config{
+		Keys:     make(map[string][]string),
+		Sections: make(map[string]map[string][]string),
+	}
+	for key, val := range raw {
+		section, ok := val.(map[string]interface{})
+		if !ok {
+			if err := gosif_UtilAddJSONValue(config.Keys, key, val); err != nil {
+				return nil, fmt.Errorf("config file %s: key \"%s\": %w", path, key, err)
+			}
+			continue
+		}
+		config.Sections[key] = make(map[string][]string)
+		for sectionKey, sectionVal := range section {
+			if err := gosif_UtilAddJSONValue(config.Sections[key], sectionKey, sectionVal); err != nil {
+				return nil, fmt.Errorf("config file %s: key \"%s.%s\": %w", path, key, sectionKey, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+func gosif_UtilAddJSONValue(values map[string][]string, key string, val interface{}) error {
+	if val == nil {
+		return nil
+	}
+	elems, isArray := val.([]interface{})
+	if !isArray {
+		elems = []interface{}{val}
+	}
+	args := make([]string, len(elems))
+	for i, elem := range elems {
+		switch v := elem.(type) {
+		case string:
+			args[i] = v
+		case json.Number:
+			args[i] = v.String()
+		case bool:
+			args[i] = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("unexpected value %v, expected a string, a number, a boolean or an array of those", elem)
+		}
+	}
+	values[key] = args
+	return nil
+}
+
+// gosif_UtilParseINIConfig parses the "key = value" lines of an INI file, the
+// "[Function]" lines start the functions sections and the lines starting
+// with "#" or ";" are comments.
+func gosif_UtilParseINIConfig(path string, content string) (*gosif_Config, error) {
+	config := &gosif_Config{
+		Keys:     make(map[string][]string),
+		Sections: make(map[string]map[string][]string),
+	}
+	values := config.Keys
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := config.Sections[section]; !ok {
+				config.Sections[section] = make(map[string][]string)
+			}
+			values = config.Sections[section]
+			continue
+		}
+		sepIdx := strings.Index(line, "=")
+		if sepIdx <= 0 {
+			return nil, fmt.Errorf("config file %s, line %d: expected \"key = value\" or \"[section]\", got \"%s\"", path, i+1, line)
+		}
+		key := strings.TrimSpace(line[:sepIdx])
+		val := strings.TrimSpace(line[sepIdx+1:])
+		if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+			unquoted, err := strconv.Unquote(val)
+			if err != nil {
+				return nil, fmt.Errorf("config file %s, line %d: %w", path, i+1, err)
+			}
+			val = unquoted
+		}
+		if _, ok := values[key]; ok {
+			return nil, fmt.Errorf("config file %s, line %d: the key \"%s\" is set more than once", path, i+1, key)
+		}
+		values[key] = []string{val}
+	}
+	return config, nil
+}
+
+// gosif_UnknownFlagError is returned when a passed flag does not match any
+// of the function flags.
+type gosif_UnknownFlagError struct {
+	// Flag is the flag as it was passed, e.g. --nme
+	Flag string
+	// Suggestion is the closest flag name if there is one, e.g. --name
+	Suggestion string
+	// Candidates are the flags that an ambiguous prefix may refer to
+	Candidates []string
+}
+
+func (e *gosif_UnknownFlagError) Error() string {
+	if len(e.Candidates) != 0 {
+		return fmt.Sprintf("an ambiguous flag \"%s\" found, it may refer to %s", e.Flag, strings.Join(e.Candidates, ", "))
+	}
+	if len(e.Suggestion) == 0 {
+		return fmt.Sprintf("an unexpected flag \"%s\" found", e.Flag)
+	}
+	return fmt.Sprintf("an unexpected flag \"%s\" found, did you mean %s?", e.Flag, e.Suggestion)
+}
+
+// gosif_MissingFlagError is returned when a required flag was not passed.
+type gosif_MissingFlagError struct {
+	// Flag is the long form of the flag, e.g. --name
+	Flag string
+}
+
+func (e *gosif_MissingFlagError) Error() string {
+	return fmt.Sprintf("a required flag \"%s\" was not passed", e.Flag)
+}
+
+// gosif_UtilMissingFlag returns the error reporting that the required flag
+// name was not passed.
+func gosif_UtilMissingFlag(name string) error {
+	return &gosif_MissingFlagError{Flag: "--" + name}
+}
+
+// gosif_CastError is returned when an argument of a flag cannot be cast to
+// the flag type, it wraps the error of the cast function.
+type gosif_CastError struct {
+	Flag  string
+	Value string
+	Type  string
+	// Source is where the argument was read from if it was not passed on
+	// the command line
+	Source string
+	Err    error
+}
+
+func (e *gosif_CastError) Error() string {
+	if len(e.Source) == 0 {
+		return fmt.Sprintf("cast failed: %v", e.Err)
+	}
+	return fmt.Sprintf("cast failed: %s: %v", e.Source, e.Err)
+}
+
+func (e *gosif_CastError) Unwrap() error {
+	return e.Err
+}
+
+// gosif_ArityError is returned when a flag is passed with a wrong number of
+// arguments, when a flag that takes a single value is repeated, or when too
+// many positional arguments are passed.
+type gosif_ArityError struct {
+	// Flag is the flag as it was passed, it is empty for the positional
+	// arguments
+	Flag string
+	// Expected is the number of the arguments that the flag takes, or the
+	// maximal number of them if AtMost is set
+	Expected int
+	AtMost   bool
+	Args     []string
+	// Previous is the flag as it was passed the first time if it is
+	// repeated
+	Previous string
+}
+
+func (e *gosif_ArityError) Error() string {
+	switch {
+	case len(e.Previous) != 0:
+		return fmt.Sprintf("the flag %s was passed more than once (previously as %s)", e.Flag, e.Previous)
+	case len(e.Flag) == 0 && e.Expected == 0:
+		return fmt.Sprintf("unexpected positional arguments %v: the function does not accept positional arguments", e.Args)
+	case len(e.Flag) == 0:
+		return fmt.Sprintf("too many positional arguments: expected at most %d, got %d (%v)", e.Expected, len(e.Args), e.Args)
+	}
+	expected := fmt.Sprintf("%d argument", e.Expected)
+	if e.Expected != 1 {
+		expected += "s"
+	}
+	if e.AtMost {
+		expected = "at most " + expected
+	}
+	return fmt.Sprintf("flag %s: expected %s, but got %d (%v)", e.Flag, expected, len(e.Args), e.Args)
+}
+
+// gosif_UtilCastError reports the source of the arguments that failed to be
+// cast unless they were passed on the command line.
+func gosif_UtilCastError(parsedFlag gosif_ReadFlag, arg string, typ string, err error) error {
+	return &gosif_CastError{
+		Flag:   parsedFlag.PassedFlag,
+		Value:  arg,
+		Type:   typ,
+		Source: parsedFlag.Source,
+		Err:    err,
+	}
+}
+
+// gosif_UtilStoreFlag stores the read flag, the arguments of a repeated
+// slice flag are appended to the previous ones.
+func gosif_UtilStoreFlag(parsedFlags map[string]gosif_ReadFlag, flagName string, readFlag gosif_ReadFlag, spec *gosif_ArgsSpec) error {
+	prev, ok := parsedFlags[flagName]
+	if !ok || spec.LastWinsFlags[flagName] {
+		parsedFlags[flagName] = readFlag
+		return nil
+	}
+	if !spec.SliceFlags[flagName] {
+		return &gosif_ArityError{Flag: readFlag.PassedFlag, Args: readFlag.Args, Previous: prev.PassedFlag}
+	}
+	parsedFlags[flagName] = gosif_ReadFlag{
+		PassedFlag: readFlag.PassedFlag,
+		Args:       append(prev.Args, readFlag.Args...),
+	}
+	return nil
+}
+
+// gosif_UtilResolveFlags returns the parameters that the flag refers to
+// along with the flags as they should be reported: a single parameter, or
+// several for a cluster of single-letter flags (e.g. -xvf).
+func gosif_UtilResolveFlags(passedFlag string, extracted string, spec *gosif_ArgsSpec) ([]string, []string, error) {
+	if flagName, ok := spec.Flags[extracted]; ok {
+		return []string{passedFlag}, []string{flagName}, nil
+	}
+	if passedFlags, flagNames, ok := gosif_UtilSplitCluster(passedFlag, extracted, spec); ok {
+		return passedFlags, flagNames, nil
+	}
+	flagName, err := gosif_UtilResolveFlag(passedFlag, extracted, spec.Flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{passedFlag}, []string{flagName}, nil
+}
+
+// gosif_UtilSplitCluster splits a single-dash cluster of single-letter flags,
+// all the flags except the last one must be boolean flags or counters.
+func gosif_UtilSplitCluster(passedFlag string, extracted string, spec *gosif_ArgsSpec) ([]string, []string, bool) {
+	letters := []rune(extracted)
+	if strings.HasPrefix(passedFlag, "--") || len(letters) < 2 {
+		return nil, nil, false
+	}
+	passedFlags := make([]string, len(letters))
+	flagNames := make([]string, len(letters))
+	for i, l := range letters {
+		flagName, ok := spec.Flags[string(l)]
+		if !ok {
+			return nil, nil, false
+		}
+		if i != len(letters)-1 && !spec.BoolFlags[flagName] && !spec.CountFlags[flagName] {
+			return nil, nil, false
+		}
+		passedFlags[i] = "-" + string(l)
+		flagNames[i] = flagName
+	}
+	return passedFlags, flagNames, true
+}
+
+// gosif_UtilResolveFlag returns the name of the parameter that the flag
+// refers to, the flag is either one of the accepted names or an unambiguous
+// prefix of them.
+func gosif_UtilResolveFlag(passedFlag string, extracted string, funcFlags map[string]string) (string, error) {
+	if flagName, ok := funcFlags[extracted]; ok {
+		return flagName, nil
+	}
+	candidates := gosif_UtilMatchFlagPrefix(extracted, funcFlags)
+	if len(candidates) == 0 {
+		aliases := make([]string, 0, len(funcFlags))
+		for alias := range funcFlags {
+			aliases = append(aliases, alias)
+		}
+		if suggestion, ok := gosif_UtilSuggest(extracted, aliases); ok {
+			dashes := "--"
+			if len([]rune(suggestion)) == 1 {
+				dashes = "-"
+			}
+			return "", &gosif_UnknownFlagError{Flag: passedFlag, Suggestion: dashes + suggestion}
+		}
+		return "", &gosif_UnknownFlagError{Flag: passedFlag}
+	}
+	if len(candidates) > 1 {
+		dashes := passedFlag[:len(passedFlag)-len(extracted)]
+		names := make([]string, 0, len(candidates))
+		for alias := range candidates {
+			names = append(names, dashes+alias)
+		}
+		sort.Strings(names)
+		return "", &gosif_UnknownFlagError{Flag: passedFlag, Candidates: names}
+	}
+	for _, flagName := range candidates {
+		return flagName, nil
+	}
+	return "", nil
+}
+
+// gosif_UtilMatchFlagPrefix returns the flags names that start with the prefix
+// mapped to the parameters names, the flags that refer to the same parameter
+// are reported once under the longest (i.e. the full) name.
+func gosif_UtilMatchFlagPrefix(prefix string, funcFlags map[string]string) map[string]string {
+	byParam := make(map[string]string)
+	for alias, flagName := range funcFlags {
+		if !strings.HasPrefix(alias, prefix) {
+			continue
+		}
+		prev, ok := byParam[flagName]
+		if !ok || len(alias) > len(prev) || (len(alias) == len(prev) && alias < prev) {
+			byParam[flagName] = alias
+		}
+	}
+	candidates := make(map[string]string, len(byParam))
+	for flagName, alias := range byParam {
+		candidates[alias] = flagName
+	}
+	return candidates
+}
+
+// gosif_UtilSplitAssignment splits the "flag=value" form of the extracted
+// flag into the flag name and the assigned value.
+func gosif_UtilSplitAssignment(extracted string) (string, string, bool) {
+	eqPos := strings.IndexByte(extracted, '=')
+	if eqPos <= 0 {
+		return extracted, "", false
+	}
+	return extracted[:eqPos], extracted[eqPos+1:], true
+}
+
+func gosif_UtilAssignedArgs(val string, isSlice bool) []string {
+	if isSlice && len(val) == 0 {
+		return []string{}
+	}
+	return []string{val}
+}
+
+// gosif_SplitArgs splits each of the arguments around the separator, a
+// backslash escapes the separator and itself.
+func gosif_SplitArgs(args []string, sep string) []string {
+	splitArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		var sb strings.Builder
+		for i := 0; i < len(arg); {
+			if arg[i] == '\\' && i+1 < len(arg) {
+				if strings.HasPrefix(arg[i+1:], sep) {
+					sb.WriteString(sep)
+					i += 1 + len(sep)
+					continue
+				}
+				if arg[i+1] == '\\' {
+					sb.WriteByte('\\')
+					i += 2
+					continue
+				}
+			}
+			if strings.HasPrefix(arg[i:], sep) {
+				splitArgs = append(splitArgs, sb.String())
+				sb.Reset()
+				i += len(sep)
+				continue
+			}
+			sb.WriteByte(arg[i])
+			i++
+		}
+		splitArgs = append(splitArgs, sb.String())
+	}
+	return splitArgs
+}
+
+func gosif_UtilIsFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+// gosif_UtilBindPositional binds the positional arguments to the positional
+// parameters in order.
+func gosif_UtilBindPositional(positional []string, spec *gosif_ArgsSpec, parsedFlags map[string]gosif_ReadFlag) error {
+	if len(positional) == 0 {
+		return nil
+	}
+	if len(spec.Positional) == 0 {
+		return &gosif_ArityError{Args: positional}
+	}
+	if len(positional) > len(spec.Positional) && !spec.CollectRest {
+		return &gosif_ArityError{Expected: len(spec.Positional), AtMost: true, Args: positional}
+	}
+	for i, arg := range positional {
+		posIdx := i
+		if posIdx >= len(spec.Positional) {
+			posIdx = len(spec.Positional) - 1
+		}
+		posName := spec.Positional[posIdx]
+		flagName, ok := spec.Flags[posName]
+		if !ok {
+			return fmt.Errorf("internal error: the positional parameter %s is not a known flag", posName)
+		}
+		passedName := fmt.Sprintf("<%s>", posName)
+		parsedFlag, ok := parsedFlags[flagName]
+		if ok && parsedFlag.PassedFlag != passedName {
+			return fmt.Errorf("the argument %s was passed both with the flag %s and as the positional argument \"%s\"", passedName, parsedFlag.PassedFlag, arg)
+		}
+		parsedFlags[flagName] = gosif_ReadFlag{
+			PassedFlag: passedName,
+			Args:       append(parsedFlag.Args, arg),
+		}
+	}
+	return nil
+}
+
 func gosif_UtilExtractFlag(f string) (string, error) {
 	if len(f) == 0 {
 		return "", fmt.Errorf("internal error: expected a flag, got an empty string")
@@ -200,7 +1724,7 @@ func gosif_UtilExtractFlagAfterDash(f string) string {
 	return f[2:]
 }
 
-func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]struct{}) ([]string, error) {
+func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]string) ([]string, error) {
 	flagArgs := make([]string, 0)
 	for _, a := range args {
 		if a[0] == '-' {
@@ -208,9 +1732,23 @@ func gosif_UtilReadFlagArgs(args []string, funcFlags map[string]struct{}) ([]str
 			if extracted == "--" {
 				break
 			}
+			extracted, _, _ = gosif_UtilSplitAssignment(extracted)
 			if _, ok := funcFlags[extracted]; ok {
 				break
 			}
+			if _, ok := funcFlags[strings.TrimPrefix(extracted, "no-")]; ok {
+				// a negated boolean flag
+				break
+			}
+			if len(extracted) != 0 && len(gosif_UtilMatchFlagPrefix(extracted, funcFlags)) != 0 {
+				break
+			}
+			if len(extracted) != 0 && a[1] != '-' {
+				// a possible cluster of single-letter flags
+				if _, ok := funcFlags[string([]rune(extracted)[0])]; ok {
+					break
+				}
+			}
 		}
 		extractedArg := gosif_UtilExtractArg(a)
 		if len(extractedArg) == 0 {
@@ -243,22 +1781,22 @@ func gosif_UtilExtractArg(arg string) string {
 	return arg
 }
 
-func gosif_GetFlagArg(args []string) (string, error) {
-	if len(args) == 0 {
-		return "", fmt.Errorf("no arguments passed")
-	}
-	if len(args) > 1 {
-		return "", fmt.Errorf("expected a single argument, got %d (%v)", len(args), args)
+func gosif_GetFlagArg(parsedFlag gosif_ReadFlag) (string, error) {
+	if len(parsedFlag.Args) != 1 {
+		return "", &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: 1, Args: parsedFlag.Args}
 	}
-	return args[0], nil
+	return parsedFlag.Args[0], nil
 }
 
-func gosif_GetBoolArg(args []string) (string, error) {
-	if len(args) == 0 {
+func gosif_GetBoolArg(parsedFlag gosif_ReadFlag) (string, error) {
+	if len(parsedFlag.Args) == 0 {
 		return "true", nil
 	}
-	if len(args) > 1 {
-		return "", fmt.Errorf("expected a single argument, got %d (%v)", len(args), args)
+	if len(parsedFlag.Args) > 1 {
+		return "", &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: 1, AtMost: true, Args: parsedFlag.Args}
 	}
-	return args[0], nil
+	return parsedFlag.Args[0], nil
 }`
+
+// gosifFuncsImports are the packages used by gosifFuncs besides fmt and os.
+var gosifFuncsImports = []string{"bufio", "encoding/json", "io", "sort", "strconv", "strings"}
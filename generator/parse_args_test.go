@@ -1,7 +1,12 @@
 package generator
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -83,8 +88,8 @@ func checkErrors(expectedErr error, actualErr error) error {
 
 func TestParseArgs(t *testing.T) {
 	type inArg struct {
-		args      []string
-		funcFlags map[string]struct{}
+		args []string
+		spec *gosif_ArgsSpec
 	}
 	type outArg struct {
 		parsedFlags map[string]gosif_ReadFlag
@@ -97,12 +102,14 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"-a", "aArg1", "aArg2", "-b", "bArg1", "--c", "\"-a\"", "\\\"-b\\\"", "-d", "\"\"", "-e"},
-				funcFlags: map[string]struct{}{
-					"a": {},
-					"b": {},
-					"c": {},
-					"d": {},
-					"e": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+						"c": "c",
+						"d": "d",
+						"e": "e",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{
@@ -131,8 +138,10 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"-a", "aArg1", "-b", "-b"},
-				funcFlags: map[string]struct{}{
-					"a": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{
@@ -145,8 +154,10 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"-a", "aArg", "-"},
-				funcFlags: map[string]struct{}{
-					"a": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{
@@ -159,8 +170,10 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"-a", "aArg", "---"},
-				funcFlags: map[string]struct{}{
-					"a": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{
@@ -173,10 +186,12 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{},
-				funcFlags: map[string]struct{}{
-					"a": {},
-					"b": {},
-					"c": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+						"c": "c",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{},
@@ -184,22 +199,34 @@ func TestParseArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: nil,
-				funcFlags: map[string]struct{}{
-					"a": {},
-					"b": {},
-					"c": {},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+						"c": "c",
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{},
 		},
 		{
 			in: inArg{
-				args: []string{"--a", "aArg1", "-b", "bArg1", "--b", "bArg2", "-a", "aArg3", "--b", "bArg3", "-c", "cArg1", "-c", "cArg2", "-d", "dArg1", "--d"},
-				funcFlags: map[string]struct{}{
-					"a": {},
-					"b": {},
-					"c": {},
-					"d": {},
+				args: []string{"--a", "aArg1", "-b", "bArg1", "--b", "bArg2", "-a", "aArg3", "--b", "bArg3", "-c", "cArg1", "-c", "cArg2", "-d", "dArg1", "--d", "-d=dArg2,dArg3"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+						"c": "c",
+						"d": "d",
+					},
+					SliceFlags: map[string]bool{
+						"d": true,
+					},
+					LastWinsFlags: map[string]bool{
+						"a": true,
+						"b": true,
+						"c": true,
+					},
 				},
 			},
 			expected: map[string]gosif_ReadFlag{
@@ -216,12 +243,285 @@ func TestParseArgs(t *testing.T) {
 					Args:       []string{"cArg2"},
 				},
 				"d": {
-					PassedFlag: "--d",
-					Args:       []string{},
+					PassedFlag: "-d",
+					Args:       []string{"dArg1", "dArg2,dArg3"},
+				},
+			},
+		},
+	}
+	okCases = append(okCases, struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		in: inArg{
+			args: []string{"--max-retries", "3", "--dryRun"},
+			spec: &gosif_ArgsSpec{
+				Flags: map[string]string{
+					"max-retries": "maxRetries",
+					"maxRetries":  "maxRetries",
+					"dry-run":     "dryRun",
+					"dryRun":      "dryRun",
+				},
+			},
+		},
+		expected: map[string]gosif_ReadFlag{
+			"maxRetries": {
+				PassedFlag: "--max-retries",
+				Args:       []string{"3"},
+			},
+			"dryRun": {
+				PassedFlag: "--dryRun",
+				Args:       []string{},
+			},
+		},
+	})
+	okCases = append(okCases, struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		in: inArg{
+			args: []string{"-a", "aArg", "--b=-bArg", "--c=", "-d=1,-2,3", "--e=", "--f=x=y"},
+			spec: &gosif_ArgsSpec{
+				Flags: map[string]string{
+					"a": "a",
+					"b": "b",
+					"c": "c",
+					"d": "d",
+					"e": "e",
+					"f": "f",
+				},
+				SliceFlags: map[string]bool{
+					"d": true,
+					"e": true,
+					"f": true,
 				},
 			},
 		},
+		expected: map[string]gosif_ReadFlag{
+			"a": {
+				PassedFlag: "-a",
+				Args:       []string{"aArg"},
+			},
+			"b": {
+				PassedFlag: "--b",
+				Args:       []string{"-bArg"},
+			},
+			"c": {
+				PassedFlag: "--c",
+				Args:       []string{""},
+			},
+			"d": {
+				PassedFlag: "-d",
+				Args:       []string{"1,-2,3"},
+			},
+			"e": {
+				PassedFlag: "--e",
+				Args:       []string{},
+			},
+			"f": {
+				PassedFlag: "--f",
+				Args:       []string{"x=y"},
+			},
+		},
+	})
+	okCases = append(okCases, struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		in: inArg{
+			args: []string{"--xs", "1", "-2", "--max-r", "3", "--maxS=5", "-d"},
+			spec: &gosif_ArgsSpec{
+				Flags: map[string]string{
+					"xs":          "xs",
+					"x":           "xs",
+					"max-retries": "maxRetries",
+					"maxRetries":  "maxRetries",
+					"max-size":    "maxSize",
+					"maxSize":     "maxSize",
+					"dry-run":     "dryRun",
+					"dryRun":      "dryRun",
+				},
+			},
+		},
+		expected: map[string]gosif_ReadFlag{
+			"xs": {
+				PassedFlag: "--xs",
+				Args:       []string{"1", "-2"},
+			},
+			"maxRetries": {
+				PassedFlag: "--max-r",
+				Args:       []string{"3"},
+			},
+			"maxSize": {
+				PassedFlag: "--maxS",
+				Args:       []string{"5"},
+			},
+			"dryRun": {
+				PassedFlag: "-d",
+				Args:       []string{},
+			},
+		},
+	})
+	clusterSpec := &gosif_ArgsSpec{
+		Flags: map[string]string{
+			"all":     "all",
+			"a":       "all",
+			"verbose": "verbose",
+			"v":       "verbose",
+			"file":    "file",
+			"f":       "file",
+		},
+		BoolFlags: map[string]bool{
+			"all": true,
+		},
+		CountFlags: map[string]bool{
+			"verbose": true,
+		},
 	}
+	okCases = append(okCases, struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		in: inArg{
+			args: []string{"--tags", "a", "--no-dry-run", "--no-cache"},
+			spec: &gosif_ArgsSpec{
+				Flags: map[string]string{
+					"tags":    "tags",
+					"dry-run": "dryRun",
+					"cache":   "cache",
+				},
+				Negations: map[string]string{
+					"no-dry-run": "dryRun",
+					"no-cache":   "cache",
+				},
+			},
+		},
+		expected: map[string]gosif_ReadFlag{
+			"tags": {
+				PassedFlag: "--tags",
+				Args:       []string{"a"},
+			},
+			"dryRun": {
+				PassedFlag: "--no-dry-run",
+				Args:       []string{"false"},
+			},
+			"cache": {
+				PassedFlag: "--no-cache",
+				Args:       []string{"false"},
+			},
+		},
+	})
+	okCases = append(okCases, []struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		{
+			in: inArg{
+				args: []string{"-avvf", "out.tar", "--verbose"},
+				spec: clusterSpec,
+			},
+			expected: map[string]gosif_ReadFlag{
+				"all": {
+					PassedFlag: "-a",
+					Args:       []string{},
+				},
+				"verbose": {
+					PassedFlag: "--verbose",
+					Args:       []string{"3"},
+				},
+				"file": {
+					PassedFlag: "-f",
+					Args:       []string{"out.tar"},
+				},
+			},
+		},
+		{
+			in: inArg{
+				args: []string{"-f", "out.tar", "-va=false"},
+				spec: clusterSpec,
+			},
+			expected: map[string]gosif_ReadFlag{
+				"all": {
+					PassedFlag: "-a",
+					Args:       []string{"false"},
+				},
+				"verbose": {
+					PassedFlag: "-v",
+					Args:       []string{"1"},
+				},
+				"file": {
+					PassedFlag: "-f",
+					Args:       []string{"out.tar"},
+				},
+			},
+		},
+	}...)
+	okCases = append(okCases, []struct {
+		in       inArg
+		expected map[string]gosif_ReadFlag
+	}{
+		{
+			in: inArg{
+				args: []string{"-c", "cArg", "--", "aArg", "-bArg"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+						"c": "c",
+					},
+					Positional: []string{"a", "b", "c"},
+				},
+			},
+			expected: map[string]gosif_ReadFlag{
+				"a": {
+					PassedFlag: "<a>",
+					Args:       []string{"aArg"},
+				},
+				"b": {
+					PassedFlag: "<b>",
+					Args:       []string{"-bArg"},
+				},
+				"c": {
+					PassedFlag: "-c",
+					Args:       []string{"cArg"},
+				},
+			},
+		},
+		{
+			in: inArg{
+				args: []string{"prod", "\"-v1\"", "--dry-run", "--", "a", "b"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"env":     "env",
+						"version": "version",
+						"files":   "files",
+						"dry-run": "dryRun",
+					},
+					Positional:        []string{"env", "version", "files"},
+					LeadingPositional: true,
+					CollectRest:       true,
+				},
+			},
+			expected: map[string]gosif_ReadFlag{
+				"env": {
+					PassedFlag: "<env>",
+					Args:       []string{"prod"},
+				},
+				"version": {
+					PassedFlag: "<version>",
+					Args:       []string{"-v1"},
+				},
+				"files": {
+					PassedFlag: "<files>",
+					Args:       []string{"a", "b"},
+				},
+				"dryRun": {
+					PassedFlag: "--dry-run",
+					Args:       []string{},
+				},
+			},
+		},
+	}...)
 	eqMapStringParsedFlag := func(actual map[string]gosif_ReadFlag, expected map[string]gosif_ReadFlag) error {
 		if actual == nil && expected == nil {
 			return nil
@@ -253,7 +553,7 @@ func TestParseArgs(t *testing.T) {
 		i, tc := i, tc
 		t.Run(fmt.Sprintf("ok test case #%d", i), func(t *testing.T) {
 			t.Parallel()
-			actual, err := gosif_ReadArgs(tc.in.args, tc.in.funcFlags)
+			actual, err := gosif_ReadArgs(tc.in.args, tc.in.spec)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -268,26 +568,144 @@ func TestParseArgs(t *testing.T) {
 	}{
 		{
 			in: inArg{
-				args:      []string{"-a", "aArg", "-b", "bArg", "-c", "cArg"},
-				funcFlags: nil,
+				args: []string{"-a", "aArg", "-b", "bArg", "-c", "cArg"},
+				spec: nil,
 			},
 			expected: fmt.Errorf("internal error: function flags map cannot be empty"),
 		},
 		{
 			in: inArg{
-				args:      []string{"-a", "aArg", "-b", "bArg", "-c", "cArg"},
-				funcFlags: map[string]struct{}{},
+				args: []string{"-a", "aArg", "-b", "bArg", "-c", "cArg"},
+				spec: &gosif_ArgsSpec{},
 			},
 			expected: fmt.Errorf("internal error: function flags map cannot be empty"),
 		},
 		{
 			in: inArg{
-				args: []string{"-a", "aArg", "--"},
-				funcFlags: map[string]struct{}{
-					"a": {},
+				args: []string{"-a", "aArg", "--", "b"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
+				},
+			},
+			expected: fmt.Errorf("unexpected positional arguments [b]: the function does not accept positional arguments"),
+		},
+		{
+			in: inArg{
+				args: []string{"--a=aArg", "aArg2"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
+				},
+			},
+			expected: fmt.Errorf("an error occurred during the flag \"aArg2\" extraction: expected a flag (e.g. --flag), got an argument \"aArg2\""),
+		},
+		{
+			in: inArg{
+				args: []string{"--max", "3"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"max-retries": "maxRetries",
+						"maxRetries":  "maxRetries",
+						"max-size":    "maxSize",
+						"maxSize":     "maxSize",
+					},
+				},
+			},
+			expected: fmt.Errorf("an ambiguous flag \"--max\" found, it may refer to --max-retries, --max-size"),
+		},
+		{
+			in: inArg{
+				args: []string{"-fa", "out.tar"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "all",
+						"f": "file",
+					},
+					BoolFlags: map[string]bool{
+						"all": true,
+					},
+				},
+			},
+			expected: fmt.Errorf("an unexpected flag \"-fa\" found"),
+		},
+		{
+			in: inArg{
+				args: []string{"--a", "aArg1", "-a", "aArg2"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
+				},
+			},
+			expected: fmt.Errorf("the flag -a was passed more than once (previously as --a)"),
+		},
+		{
+			in: inArg{
+				args: []string{"--no-a=true"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
+					Negations: map[string]string{
+						"no-a": "a",
+					},
+				},
+			},
+			expected: fmt.Errorf("the flag --no-a does not take a value"),
+		},
+		{
+			in: inArg{
+				args: []string{"--b=bArg"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+					},
+				},
+			},
+			expected: fmt.Errorf("an unexpected flag \"--b\" found"),
+		},
+		{
+			in: inArg{
+				args: []string{"-a", "aArg", "--", "b", "c"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+					},
+					Positional: []string{"b"},
+				},
+			},
+			expected: fmt.Errorf("too many positional arguments: expected at most 1, got 2 ([b c])"),
+		},
+		{
+			in: inArg{
+				args: []string{"bArg", "-b", "bArg"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+					},
+					Positional:        []string{"b"},
+					LeadingPositional: true,
+				},
+			},
+			expected: fmt.Errorf("the argument <b> was passed both with the flag -b and as the positional argument \"bArg\""),
+		},
+		{
+			in: inArg{
+				args: []string{"aArg", "-b", "bArg"},
+				spec: &gosif_ArgsSpec{
+					Flags: map[string]string{
+						"a": "a",
+						"b": "b",
+					},
+					Positional: []string{"a"},
 				},
 			},
-			expected: fmt.Errorf("\"--\" found, but ending command options and passing positional arguments is NYI"),
+			expected: fmt.Errorf("an error occurred during the flag \"aArg\" extraction: expected a flag (e.g. --flag), got an argument \"aArg\""),
 		},
 	}
 
@@ -295,7 +713,7 @@ func TestParseArgs(t *testing.T) {
 		i, tc := i, tc
 		t.Run(fmt.Sprintf("error test case #%d", i), func(t *testing.T) {
 			t.Parallel()
-			actual, err := gosif_ReadArgs(tc.in.args, tc.in.funcFlags)
+			actual, err := gosif_ReadArgs(tc.in.args, tc.in.spec)
 			if err == nil || actual != nil {
 				t.Fatalf("expected an error, but parsed flags %v and an error %v were returned", actual, err)
 			}
@@ -306,6 +724,64 @@ func TestParseArgs(t *testing.T) {
 	}
 }
 
+func TestSplitArgs(t *testing.T) {
+	type inArg struct {
+		args []string
+		sep  string
+	}
+	cases := []struct {
+		in       inArg
+		expected []string
+	}{
+		{
+			in:       inArg{args: []string{"1,2,3"}, sep: ","},
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			in:       inArg{args: []string{"1,2", "3", "-4,5"}, sep: ","},
+			expected: []string{"1", "2", "3", "-4", "5"},
+		},
+		{
+			in:       inArg{args: []string{"a\\,b,c"}, sep: ","},
+			expected: []string{"a,b", "c"},
+		},
+		{
+			in:       inArg{args: []string{"a\\\\,b"}, sep: ","},
+			expected: []string{"a\\", "b"},
+		},
+		{
+			in:       inArg{args: []string{"a\\b,c\\"}, sep: ","},
+			expected: []string{"a\\b", "c\\"},
+		},
+		{
+			in:       inArg{args: []string{",a,,"}, sep: ","},
+			expected: []string{"", "a", "", ""},
+		},
+		{
+			in:       inArg{args: []string{""}, sep: ","},
+			expected: []string{""},
+		},
+		{
+			in:       inArg{args: []string{"/bin::/usr/bin\\::x"}, sep: "::"},
+			expected: []string{"/bin", "/usr/bin::x"},
+		},
+		{
+			in:       inArg{args: []string{}, sep: ","},
+			expected: []string{},
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := gosif_SplitArgs(tc.in.args, tc.in.sep)
+			if err := eqStrSlices(actual, tc.expected); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
 func TestUtilExtractArg(t *testing.T) {
 	cases := []struct {
 		in       string
@@ -364,7 +840,7 @@ func TestUtilExtractArg(t *testing.T) {
 func TestUtilReadFlagArgs(t *testing.T) {
 	type inArg struct {
 		args      []string
-		funcFlags map[string]struct{}
+		funcFlags map[string]string
 	}
 	type okCaseNoFlagsBase struct {
 		inArgs   []string
@@ -380,16 +856,16 @@ func TestUtilReadFlagArgs(t *testing.T) {
 			cases[i*2] = okCase{
 				in: inArg{
 					args:      b.inArgs,
-					funcFlags: map[string]struct{}{},
+					funcFlags: map[string]string{},
 				},
 				expected: b.expected,
 			}
 			cases[i*2+1] = okCase{
 				in: inArg{
 					args: b.inArgs,
-					funcFlags: map[string]struct{}{
-						"notPassedFlag1": {},
-						"notPassedFlag2": {},
+					funcFlags: map[string]string{
+						"notPassedFlag1": "notPassedFlag1",
+						"notPassedFlag2": "notPassedFlag2",
 					},
 				},
 				expected: b.expected,
@@ -441,8 +917,8 @@ func TestUtilReadFlagArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"a", "b", "c", "d"},
-				funcFlags: map[string]struct{}{
-					"c": {},
+				funcFlags: map[string]string{
+					"c": "c",
 				},
 			},
 			expected: []string{"a", "b", "c", "d"},
@@ -450,8 +926,8 @@ func TestUtilReadFlagArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"a", "-b", "c", "d"},
-				funcFlags: map[string]struct{}{
-					"b": {},
+				funcFlags: map[string]string{
+					"b": "b",
 				},
 			},
 			expected: []string{"a"},
@@ -459,8 +935,8 @@ func TestUtilReadFlagArgs(t *testing.T) {
 		{
 			in: inArg{
 				args: []string{"a", "b", "-c", "d"},
-				funcFlags: map[string]struct{}{
-					"b": {},
+				funcFlags: map[string]string{
+					"b": "b",
 				},
 			},
 			expected: []string{"a", "b", "-c", "d"},
@@ -496,3 +972,513 @@ func eqStrSlices(actual []string, expected []string) error {
 	}
 	return nil
 }
+
+func TestUtilExtractConfigPath(t *testing.T) {
+	cases := []struct {
+		in           []string
+		expectedArgs []string
+		expectedPath string
+		expectedErr  error
+	}{
+		{
+			in:           []string{"bin", "Deploy", "--config", "a.json"},
+			expectedArgs: []string{"bin", "Deploy", "--config", "a.json"},
+		},
+		{
+			in:           []string{"bin", "--config", "a.json", "Deploy", "-r", "1"},
+			expectedArgs: []string{"bin", "Deploy", "-r", "1"},
+			expectedPath: "a.json",
+		},
+		{
+			in:           []string{"bin", "--config=conf.ini", "Deploy"},
+			expectedArgs: []string{"bin", "Deploy"},
+			expectedPath: "conf.ini",
+		},
+		{
+			in:           []string{"bin"},
+			expectedArgs: []string{"bin"},
+		},
+		{
+			in:          []string{"bin", "--config"},
+			expectedErr: fmt.Errorf("the option --config expects a file path"),
+		},
+		{
+			in:          []string{"bin", "--config=", "Deploy"},
+			expectedErr: fmt.Errorf("the option --config expects a file path"),
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			args, path, err := gosif_UtilExtractConfigPath(tc.in)
+			if err := checkErrors(tc.expectedErr, err); err != nil {
+				t.Fatal(err)
+			}
+			if err := eqStrSlices(args, tc.expectedArgs); err != nil {
+				t.Fatal(err)
+			}
+			if path != tc.expectedPath {
+				t.Fatalf("actual path %s and expected path %s are not equal", path, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestUtilParseConfig(t *testing.T) {
+	cases := []struct {
+		path        string
+		content     string
+		expected    *gosif_Config
+		expectedErr error
+	}{
+		{
+			path:    "conf.ini",
+			content: "# defaults\nreplicas = 3\n\n[Deploy]\n; the target\nenv=prod\ntags = \"a, b\"\n[Login]\n",
+			expected: &gosif_Config{
+				Keys: map[string][]string{"replicas": {"3"}},
+				Sections: map[string]map[string][]string{
+					"Deploy": {"env": {"prod"}, "tags": {"a, b"}},
+					"Login":  {},
+				},
+			},
+		},
+		{
+			path:        "conf.ini",
+			content:     "replicas = 3\nverbose\n",
+			expectedErr: fmt.Errorf("config file conf.ini, line 2: expected \"key = value\" or \"[section]\", got \"verbose\""),
+		},
+		{
+			path:        "conf.ini",
+			content:     "[Deploy]\nenv = a\nenv = b\n",
+			expectedErr: fmt.Errorf("config file conf.ini, line 3: the key \"env\" is set more than once"),
+		},
+		{
+			path:    "conf.json",
+			content: `{"replicas": 3, "ratio": 0.5, "user": null, "Deploy": {"env": "prod", "tags": ["a", "b"], "dry-run": true}}`,
+			expected: &gosif_Config{
+				Keys: map[string][]string{"replicas": {"3"}, "ratio": {"0.5"}},
+				Sections: map[string]map[string][]string{
+					"Deploy": {"env": {"prod"}, "tags": {"a", "b"}, "dry-run": {"true"}},
+				},
+			},
+		},
+		{
+			path:        "conf.json",
+			content:     `{"Deploy": {"tags": [["a"]]}}`,
+			expectedErr: fmt.Errorf("config file conf.json: key \"Deploy.tags\": unexpected value [a], expected a string, a number, a boolean or an array of those"),
+		},
+		{
+			path:        "conf.json",
+			content:     `["a"]`,
+			expectedErr: fmt.Errorf("config file conf.json: json: cannot unmarshal array into Go value of type map[string]interface {}"),
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			var actual *gosif_Config
+			var err error
+			if strings.HasSuffix(tc.path, ".json") {
+				actual, err = gosif_UtilParseJSONConfig(tc.path, []byte(tc.content))
+			} else {
+				actual, err = gosif_UtilParseINIConfig(tc.path, tc.content)
+			}
+			if err := checkErrors(tc.expectedErr, err); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("actual config %v and expected config %v are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUtilSuggest(t *testing.T) {
+	commands := []string{"PrintStringMaybeUpper", "Deploy", "Destroy", "print-retries", "PrintRetries"}
+	cases := []struct {
+		name          string
+		candidates    []string
+		expected      string
+		expectedFound bool
+	}{
+		{
+			name:          "PrintStringMaybeUper",
+			candidates:    commands,
+			expected:      "PrintStringMaybeUpper",
+			expectedFound: true,
+		},
+		{
+			name:          "deploy",
+			candidates:    commands,
+			expected:      "Deploy",
+			expectedFound: true,
+		},
+		{
+			name:          "print_retires",
+			candidates:    commands,
+			expected:      "print-retries",
+			expectedFound: true,
+		},
+		{
+			name:       "Status",
+			candidates: commands,
+		},
+		{
+			name:          "nmae",
+			candidates:    []string{"n", "name", "upper", "u"},
+			expected:      "name",
+			expectedFound: true,
+		},
+		{
+			name:       "fa",
+			candidates: []string{"f", "a", "file", "all"},
+		},
+		{
+			name: "name",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual, found := gosif_UtilSuggest(tc.name, tc.candidates)
+			if found != tc.expectedFound {
+				t.Fatalf("expected found to be %t, got %t (%s)", tc.expectedFound, found, actual)
+			}
+			if found && actual != tc.expected {
+				t.Fatalf("actual suggestion %s and expected suggestion %s are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUtilExtractInteractive(t *testing.T) {
+	cases := []struct {
+		in                  []string
+		expectedArgs        []string
+		expectedInteractive bool
+	}{
+		{
+			in:                  []string{"bin", "--interactive", "Deploy", "-r", "1"},
+			expectedArgs:        []string{"bin", "Deploy", "-r", "1"},
+			expectedInteractive: true,
+		},
+		{
+			in:                  []string{"bin", "--config", "a.json", "--interactive", "Deploy"},
+			expectedArgs:        []string{"bin", "--config", "a.json", "Deploy"},
+			expectedInteractive: true,
+		},
+		{
+			in:                  []string{"bin", "--config=a.json", "--interactive", "Deploy"},
+			expectedArgs:        []string{"bin", "--config=a.json", "Deploy"},
+			expectedInteractive: true,
+		},
+		{
+			in:           []string{"bin", "Deploy", "--interactive"},
+			expectedArgs: []string{"bin", "Deploy", "--interactive"},
+		},
+		{
+			in:           []string{"bin"},
+			expectedArgs: []string{"bin"},
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			args, interactive := gosif_UtilExtractInteractive(tc.in)
+			if err := eqStrSlices(args, tc.expectedArgs); err != nil {
+				t.Fatal(err)
+			}
+			if interactive != tc.expectedInteractive {
+				t.Fatalf("expected interactive to be %t, got %t", tc.expectedInteractive, interactive)
+			}
+		})
+	}
+}
+
+func TestUtilPrompt(t *testing.T) {
+	cases := []struct {
+		prompt      gosif_Prompt
+		input       string
+		expected    gosif_ReadFlag
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			prompt:      gosif_Prompt{Name: "replicas", PassedFlag: "--replicas", Type: "int", Description: "the number of replicas"},
+			input:       "3\n",
+			expected:    gosif_ReadFlag{PassedFlag: "--replicas", Args: []string{"3"}},
+			expectedOut: "--replicas (int) the number of replicas: ",
+		},
+		{
+			prompt:      gosif_Prompt{Name: "tags", PassedFlag: "--tags", Type: "[]string", IsSlice: true},
+			input:       "\n a  b \n",
+			expected:    gosif_ReadFlag{PassedFlag: "--tags", Args: []string{"a", "b"}},
+			expectedOut: "--tags ([]string): a value is required\n--tags ([]string): ",
+		},
+		{
+			prompt:      gosif_Prompt{Name: "env", PassedFlag: "--env", Type: "string", Choices: []string{"dev", "prod"}},
+			input:       "qa\nprod",
+			expected:    gosif_ReadFlag{PassedFlag: "--env", Args: []string{"prod"}},
+			expectedOut: "--env (string) {dev|prod}: unexpected value \"qa\", expected one of [dev, prod]\n--env (string) {dev|prod}: ",
+		},
+		{
+			prompt:      gosif_Prompt{Name: "name", PassedFlag: "--name", Type: "string"},
+			input:       "",
+			expectedOut: "--name (string): \n",
+			expectedErr: fmt.Errorf("a required flag \"--name\" was not passed"),
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			var out strings.Builder
+			actual, err := gosif_UtilPrompt(bufio.NewReader(strings.NewReader(tc.input)), &out, tc.prompt)
+			if err := checkErrors(tc.expectedErr, err); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("actual flag %+v and expected flag %+v are not equal", actual, tc.expected)
+			}
+			if out.String() != tc.expectedOut {
+				t.Fatalf("actual output %q and expected output %q are not equal", out.String(), tc.expectedOut)
+			}
+		})
+	}
+}
+
+func TestUtilMenu(t *testing.T) {
+	items := []gosif_MenuItem{
+		{Name: "Deploy", Summary: "Deploy deploys the service"},
+		{Name: "Destroy"},
+		{Name: "Greet"},
+	}
+	menu := "Functions:\n  1) Deploy   Deploy deploys the service\n  2) Destroy\n  3) Greet\n"
+	prompt := "Choose a function by its number or name: "
+	cases := []struct {
+		input       string
+		expected    string
+		expectedOut string
+		expectedErr error
+	}{
+		{
+			input:       "2\n",
+			expected:    "Destroy",
+			expectedOut: menu + prompt,
+		},
+		{
+			input:       "gr\n",
+			expected:    "Greet",
+			expectedOut: menu + prompt,
+		},
+		{
+			input:       "de\n4\nx\ndep\n",
+			expected:    "Deploy",
+			expectedOut: menu + prompt + "an ambiguous name de, it may refer to Deploy, Destroy\n" + prompt + "expected a number from 1 to 3\n" + prompt + "unknown function x\n" + prompt,
+		},
+		{
+			input:       "",
+			expectedOut: menu + prompt + "\n",
+			expectedErr: fmt.Errorf("no function name passed"),
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			var out strings.Builder
+			actual, err := gosif_UtilMenu(bufio.NewReader(strings.NewReader(tc.input)), &out, items)
+			if err := checkErrors(tc.expectedErr, err); err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("actual function %s and expected function %s are not equal", actual, tc.expected)
+			}
+			if out.String() != tc.expectedOut {
+				t.Fatalf("actual output %q and expected output %q are not equal", out.String(), tc.expectedOut)
+			}
+		})
+	}
+}
+
+func TestUtilShellQuote(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected string
+	}{
+		{
+			in:       "--env=prod",
+			expected: "--env=prod",
+		},
+		{
+			in:       "prod env",
+			expected: "'prod env'",
+		},
+		{
+			in:       "it's",
+			expected: `'it'\''s'`,
+		},
+		{
+			in:       "",
+			expected: "''",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual := gosif_UtilShellQuote(tc.in)
+			if actual != tc.expected {
+				t.Fatalf("actual word %s and expected word %s are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUtilRun(t *testing.T) {
+	cases := []struct {
+		fn          func() error
+		expectedErr string
+	}{
+		{
+			fn: func() error {
+				return nil
+			},
+		},
+		{
+			fn: func() error {
+				return fmt.Errorf("failed")
+			},
+			expectedErr: "failed",
+		},
+		{
+			fn: func() error {
+				panic("boom")
+			},
+			expectedErr: "panic: boom (set GOSIF_TRACEBACK=1 to print the stack trace)",
+		},
+		{
+			fn: func() error {
+				var m map[string]int
+				m["key"] = 1
+				return nil
+			},
+			expectedErr: "panic: assignment to entry in nil map (set GOSIF_TRACEBACK=1 to print the stack trace)",
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			err := gosif_UtilRun(tc.fn)
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("actual error %v and expected error %s are not equal", err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	cases := []struct {
+		err         error
+		target      interface{}
+		expected    interface{}
+		expectedErr error
+	}{
+		{
+			err: func() error {
+				_, err := gosif_UtilResolveFlag("--nmae", "nmae", map[string]string{"name": "name"})
+				return err
+			}(),
+			target:      new(*gosif_UnknownFlagError),
+			expected:    &gosif_UnknownFlagError{Flag: "--nmae", Suggestion: "--name"},
+			expectedErr: fmt.Errorf("an unexpected flag \"--nmae\" found, did you mean --name?"),
+		},
+		{
+			err: func() error {
+				_, err := gosif_UtilResolveFlag("--na", "na", map[string]string{"name": "name", "namespace": "namespace"})
+				return err
+			}(),
+			target:      new(*gosif_UnknownFlagError),
+			expected:    &gosif_UnknownFlagError{Flag: "--na", Candidates: []string{"--name", "--namespace"}},
+			expectedErr: fmt.Errorf("an ambiguous flag \"--na\" found, it may refer to --name, --namespace"),
+		},
+		{
+			err: func() error {
+				parsedFlags := map[string]gosif_ReadFlag{"name": {PassedFlag: "-n", Args: []string{"a"}}}
+				return gosif_UtilStoreFlag(parsedFlags, "name", gosif_ReadFlag{PassedFlag: "--name", Args: []string{"b"}}, &gosif_ArgsSpec{})
+			}(),
+			target:      new(*gosif_ArityError),
+			expected:    &gosif_ArityError{Flag: "--name", Args: []string{"b"}, Previous: "-n"},
+			expectedErr: fmt.Errorf("the flag --name was passed more than once (previously as -n)"),
+		},
+		{
+			err:         gosif_UtilBindPositional([]string{"a", "b"}, &gosif_ArgsSpec{Positional: []string{"name"}}, map[string]gosif_ReadFlag{}),
+			target:      new(*gosif_ArityError),
+			expected:    &gosif_ArityError{Expected: 1, AtMost: true, Args: []string{"a", "b"}},
+			expectedErr: fmt.Errorf("too many positional arguments: expected at most 1, got 2 ([a b])"),
+		},
+		{
+			err:         gosif_UtilBindPositional([]string{"a"}, &gosif_ArgsSpec{}, map[string]gosif_ReadFlag{}),
+			target:      new(*gosif_ArityError),
+			expected:    &gosif_ArityError{Args: []string{"a"}},
+			expectedErr: fmt.Errorf("unexpected positional arguments [a]: the function does not accept positional arguments"),
+		},
+		{
+			err:         gosif_CheckRequiredFlags(map[string]bool{"name": false}),
+			target:      new(*gosif_MissingFlagError),
+			expected:    &gosif_MissingFlagError{Flag: "--name"},
+			expectedErr: fmt.Errorf("a required flag \"--name\" was not passed"),
+		},
+		{
+			err: func() error {
+				_, err := gosif_GetFlagArg(gosif_ReadFlag{PassedFlag: "-n", Args: []string{"1", "2"}})
+				return fmt.Errorf("wrapped: %w", err)
+			}(),
+			target:      new(*gosif_ArityError),
+			expected:    &gosif_ArityError{Flag: "-n", Expected: 1, Args: []string{"1", "2"}},
+			expectedErr: fmt.Errorf("wrapped: flag -n: expected 1 argument, but got 2 ([1 2])"),
+		},
+		{
+			err: func() error {
+				_, err := gosif_GetBoolArg(gosif_ReadFlag{PassedFlag: "-v", Args: []string{"true", "false"}})
+				return err
+			}(),
+			target:      new(*gosif_ArityError),
+			expected:    &gosif_ArityError{Flag: "-v", Expected: 1, AtMost: true, Args: []string{"true", "false"}},
+			expectedErr: fmt.Errorf("flag -v: expected at most 1 argument, but got 2 ([true false])"),
+		},
+		{
+			err:         gosif_UtilCastError(gosif_ReadFlag{PassedFlag: "-n", Source: "environment variable N"}, "x", "int", strconv.ErrSyntax),
+			target:      new(*gosif_CastError),
+			expected:    &gosif_CastError{Flag: "-n", Value: "x", Type: "int", Source: "environment variable N", Err: strconv.ErrSyntax},
+			expectedErr: fmt.Errorf("cast failed: environment variable N: invalid syntax"),
+		},
+	}
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			if err := checkErrors(tc.expectedErr, tc.err); err != nil {
+				t.Fatal(err)
+			}
+			if !errors.As(tc.err, tc.target) {
+				t.Fatalf("the error %v cannot be unwrapped as %T", tc.err, tc.target)
+			}
+			actual := reflect.ValueOf(tc.target).Elem().Interface()
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("actual error %#v and expected error %#v are not equal", actual, tc.expected)
+			}
+		})
+	}
+}
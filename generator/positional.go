@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// positionalDirective lists the parameters of the function that can be passed
+// as leading positional arguments, e.g. "//gosif:positional env version".
+const positionalDirective = "positional"
+
+// positionalSpec describes how the positional arguments of a function are
+// bound to its parameters.
+type positionalSpec struct {
+	// Params are the positional parameters, in order
+	Params []*FuncParamData
+	// Leading is set if the positional arguments can precede the flags,
+	// otherwise they are accepted only after "--"
+	Leading bool
+	// CollectRest is set if the last positional parameter is a slice or an
+	// array that takes all the remaining positional arguments
+	CollectRest bool
+}
+
+// getPositionalSpec returns the positional parameters of the function: the
+// ones listed in the positional directive, or the ones that can be passed
+// after "--" without ambiguity if there is no directive.
+func getPositionalSpec(fn *FuncForGenerator, params []*FuncParamData) (*positionalSpec, error) {
+	paramsByName := make(map[string]*FuncParamData, len(params))
+	for _, p := range params {
+		paramsByName[p.RawParam.Name] = p
+	}
+	spec := &positionalSpec{
+		Params: make([]*FuncParamData, 0, len(params)),
+	}
+	names := make([]string, 0, len(fn.ParsedFunc.Parameters))
+	if d := fn.ParsedFunc.Directive(positionalDirective); d != nil {
+		names = d.Fields()
+		spec.Leading = true
+	} else {
+		names = implicitPositional(fn, paramsByName)
+	}
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		p, ok := paramsByName[name]
+		if !ok {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" refers to an unknown parameter %s", positionalDirective, name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("the directive \"//gosif:%s\" lists the parameter %s more than once", positionalDirective, name)
+		}
+		seen[name] = struct{}{}
+		spec.Params = append(spec.Params, p)
+	}
+	if len(spec.Params) != 0 {
+		spec.CollectRest = spec.Params[len(spec.Params)-1].RawParam.IsAnArray()
+	}
+	return spec, nil
+}
+
+// implicitPositional returns the parameters that are bound to the arguments
+// passed after "--" without the positional directive, in the declaration
+// order: the bool flags and the counters take no value and are skipped, and
+// a slice or an array takes all the remaining arguments, so the parameters
+// that follow it are not positional.
+func implicitPositional(fn *FuncForGenerator, paramsByName map[string]*FuncParamData) []string {
+	names := make([]string, 0, len(fn.ParsedFunc.Parameters))
+	for _, rawParam := range fn.ParsedFunc.Parameters {
+		p, ok := paramsByName[rawParam.Name]
+		if !ok || p.IsCounter {
+			continue
+		}
+		if rawParam.IsAnArray() {
+			return append(names, rawParam.Name)
+		}
+		if rawParam.Type.Base.CoreType == "bool" {
+			continue
+		}
+		names = append(names, rawParam.Name)
+	}
+	return names
+}
+
+// names returns the command-line names of the positional parameters.
+func (s *positionalSpec) names() []string {
+	names := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		names[i] = p.Flag.CLIName
+	}
+	return names
+}
+
+// usage returns the usage line of the command, e.g.
+// "deploy <env> <version> [flags]".
+func (s *positionalSpec) usage(commandName string) string {
+	args := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		arg := fmt.Sprintf("<%s>", p.Flag.CLIName)
+		if s.CollectRest && i == len(s.Params)-1 {
+			arg += "..."
+		}
+		if p.IsOptional {
+			arg = fmt.Sprintf("[%s]", arg)
+		}
+		args[i] = arg
+	}
+	if len(args) == 0 {
+		return fmt.Sprintf("%s [flags]", commandName)
+	}
+	if s.Leading {
+		return fmt.Sprintf("%s %s [flags]", commandName, strings.Join(args, " "))
+	}
+	return fmt.Sprintf("%s [flags] [-- %s]", commandName, strings.Join(args, " "))
+}
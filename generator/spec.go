@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"text/template"
+
+	"github.com/SergeyShpak/gosif/parser"
+)
+
+// specSchemaVersion is the version of the JSON manifest schema, it is
+// increased whenever a field is removed or changes its meaning, while the new
+// fields are added without changing it.
+const specSchemaVersion = 1
+
+// specProgPlaceholder stands for the program name in the manifest embedded in
+// the generated code, it is replaced with the binary name at run time.
+const specProgPlaceholder = "@PROG@"
+
+// spec is the JSON manifest describing the commands of a generated binary.
+type spec struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Program       string `json:"program"`
+	// HasConfig is set if the flags values can be read from a configuration
+	// file passed with the --config option
+	HasConfig bool          `json:"hasConfig"`
+	Commands  []specCommand `json:"commands"`
+}
+
+type specCommand struct {
+	Name     string     `json:"name"`
+	Function string     `json:"function"`
+	Summary  string     `json:"summary"`
+	Doc      string     `json:"doc"`
+	Usage    string     `json:"usage"`
+	Flags    []specFlag `json:"flags"`
+	Examples []string   `json:"examples"`
+}
+
+type specFlag struct {
+	Name      string `json:"name"`
+	Parameter string `json:"parameter"`
+	ShortName string `json:"shortName"`
+	Type      string `json:"type"`
+	Optional  bool   `json:"optional"`
+	// Default is null for the required flags and for the pointers, which are
+	// left nil
+	Default     *string  `json:"default"`
+	EnvVar      string   `json:"envVar"`
+	Choices     []string `json:"choices"`
+	Description string   `json:"description"`
+}
+
+// getSpec converts the documentation of the commands into the manifest.
+func getSpec(doc *packageDoc) *spec {
+	s := &spec{
+		SchemaVersion: specSchemaVersion,
+		Program:       doc.Prog,
+		HasConfig:     doc.HasConfig,
+		Commands:      make([]specCommand, 0, len(doc.Commands)),
+	}
+	for _, cmd := range doc.Commands {
+		c := specCommand{
+			Name:     cmd.Name,
+			Function: cmd.Function,
+			Summary:  cmd.Summary,
+			Doc:      cmd.Doc,
+			Usage:    doc.Prog + " " + cmd.Usage,
+			Flags:    make([]specFlag, 0, len(cmd.Options)),
+			Examples: make([]string, 0, len(cmd.Examples)),
+		}
+		c.Examples = append(c.Examples, cmd.Examples...)
+		for _, option := range cmd.Options {
+			f := specFlag{
+				Name:        option.Name,
+				Parameter:   option.Parameter,
+				ShortName:   option.ShortName,
+				Type:        option.Type,
+				Optional:    !option.Required,
+				EnvVar:      option.EnvVar,
+				Choices:     make([]string, 0, len(option.Choices)),
+				Description: option.Description,
+			}
+			f.Choices = append(f.Choices, option.Choices...)
+			if len(option.Default) != 0 {
+				def := option.Default
+				f.Default = &def
+			}
+			c.Flags = append(c.Flags, f)
+		}
+		s.Commands = append(s.Commands, c)
+	}
+	return s
+}
+
+// marshalSpec encodes the manifest of the commands, the usage placeholders
+// such as <name> are left unescaped.
+func marshalSpec(doc *packageDoc) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(getSpec(doc)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeSpec writes the JSON manifest to <prog>.json.
+func writeSpec(doc *packageDoc, outDir string) error {
+	out, err := marshalSpec(doc)
+	if err != nil {
+		return err
+	}
+	return writeToFile(filepath.Join(outDir, doc.Prog+".json"), string(out))
+}
+
+// specImports are the packages used by tmplSpecFunction.
+var specImports = []string{"encoding/json", "path/filepath", "strings"}
+
+var tmplSpecFunction = template.Must(template.New("SpecFunction").Parse(`
+const gosif_Spec = {{printf "%q" .}}
+
+func gosif_ShowSpec(stream *os.File) {
+	prog, _ := json.Marshal(filepath.Base(os.Args[0]))
+	fmt.Fprint(stream, strings.ReplaceAll(gosif_Spec, "` + specProgPlaceholder + `", string(prog[1:len(prog)-1])))
+}
+`))
+
+var tmplMainFuncSpecCase = template.Must(template.New("MainFuncSpecCase").Parse(`
+case "__spec":
+	gosif_ShowSpec(os.Stdout)
+	os.Exit(gosif_ExitOK)`))
+
+// generateSpecFunction generates the function printing the JSON manifest of
+// the functions, which is shown by the hidden __spec command.
+func generateSpecFunction(functions []*parser.PkgFunc, opts *Options) (string, error) {
+	out, err := marshalSpec(getCommandsDoc(functions, specProgPlaceholder, opts))
+	if err != nil {
+		return "", err
+	}
+	return generateFromTemplate(tmplSpecFunction, string(out))
+}
@@ -143,7 +143,7 @@ type tmplArgCastInput struct {
 var tmplArgCast = template.Must(tmplIndirFunctionName.New("ArgCast").
 	Parse(`directVal, err := {{template "CastFunctionName" .}}(arg)
 if err != nil {
-	return nil, fmt.Errorf("cast failed: %v", err)
+	return nil, gosif_UtilCastError(parsedFlag, arg, "{{.Type}}", err)
 }
 {{- if eq .IndirectionLevel 0}}
 	val := directVal
@@ -162,17 +162,23 @@ type tmplArrayLayerInput struct {
 	Payload              string
 	IndirectionLevel     int
 	BaseIndirectionLevel int
+	// Sep is the separator of the elements passed as a single argument, the
+	// arguments are not split if it is empty
+	Sep string
 }
 
 var tmplArrayLayer = template.Must(tmplIndirArrFunctionName.New("ArrayLayer").
-	Parse(`{{- if not .ArrInfo.IsSlice -}}
+	Parse(`{{- if .Sep -}}
+parsedFlag.Args = gosif_SplitArgs(parsedFlag.Args, {{printf "%q" .Sep}})
+{{ end -}}
+{{- if not .ArrInfo.IsSlice -}}
 	{{- if eq .BaseIndirectionLevel 0 -}}
 if len(parsedFlag.Args) != {{.ArrInfo.ArrayLength}} {
-	return nil, fmt.Errorf("flag %s: expected {{.ArrInfo.ArrayLength}} argument{{if ne .ArrInfo.ArrayLength 1}}s{{end}}, but got %d (%v)", parsedFlag.PassedFlag, len(parsedFlag.Args), parsedFlag.Args)
+	return nil, &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: {{.ArrInfo.ArrayLength}}, Args: parsedFlag.Args}
 }
 	{{ else -}}
 if len(parsedFlag.Args) > {{.ArrInfo.ArrayLength}} {
-	return nil, fmt.Errorf("flag %s: expected {{.ArrInfo.ArrayLength}} argument{{if ne .ArrInfo.ArrayLength 1}}s{{end}}, but got %d (%v)", parsedFlag.PassedFlag, len(parsedFlag.Args), parsedFlag.Args)
+	return nil, &gosif_ArityError{Flag: parsedFlag.PassedFlag, Expected: {{.ArrInfo.ArrayLength}}, AtMost: true, Args: parsedFlag.Args}
 }
 {{ end -}}
 {{ end -}}
@@ -192,16 +198,17 @@ for i, arg := range parsedFlag.Args {
 {{- end -}}`))
 
 type tmplArgCastPostfixInput struct {
-	FlagName  string
-	IsPointer bool
-	InArray   bool
-	BaseType  string
+	FlagName   string
+	CLIName    string
+	IsPointer  bool
+	InArray    bool
+	IsRequired bool
 }
 
 var tmplArgCastPostfix = template.Must(template.New("ArgCastPostfix").
 	Parse(`flags.{{.FlagName}} = {{if .IsPointer}}&{{end}}val{{if .InArray}}1{{end}}
-{{- if not (or .IsPointer (and (eq .BaseType "bool") (not .InArray))) }}
-	requiredFlags["{{.FlagName}}"] = true
+{{- if .IsRequired }}
+	requiredFlags["{{.CLIName}}"] = true
 {{- end -}}`))
 
 type tmplArgCastPrefixInput struct {
@@ -214,14 +221,17 @@ var tmplArgCastPrefix = template.Must(template.New("ArgCastPrefix").
 	Parse(`
 case "{{.FlagName}}":
 	{{- if eq .LayersCount 0 }}
-	arg, err := gosif_Get{{- if eq .BaseType "bool" -}}Bool{{- else -}}Flag{{- end -}}Arg(parsedFlag.Args)
+	arg, err := gosif_Get{{- if eq .BaseType "bool" -}}Bool{{- else -}}Flag{{- end -}}Arg(parsedFlag)
 	if err != nil {
-		return nil, fmt.Errorf("could not get the argument passed to the flag \"%s\": %v", parsedFlag.PassedFlag, err)
+		return nil, err
 	}
 	{{- end -}}`))
 
 type tmplScriptsHelpFunctionInput struct {
-	ScriptsNames []string
+	ScriptsNames  []string
+	HasConfig     bool
+	HasCompletion bool
+	Menu          bool
 }
 
 var tmplScriptsHelpFunction = template.Must(template.New("ScriptsHelpFunction").
@@ -237,6 +247,22 @@ func gosif_ShowScriptsHelp(stream *os.File) {
 {{- end -}}
 To run a script pass its name as the first argument to the generated binary:
 e.g. ./generated-binary {{$exampleScriptName}}
+{{- if .HasConfig }}
+To read the flags values from a JSON or INI file pass its path before the function name:
+e.g. ./generated-binary --config config.json {{$exampleScriptName}}
+To be prompted for the missing required flags on a terminal pass --interactive before the function name:
+e.g. ./generated-binary --interactive {{$exampleScriptName}}
+{{- end }}
+{{- if .Menu }}
+To choose the function from a menu run the binary on a terminal without a function name:
+e.g. ./generated-binary
+{{- end }}
+To print the version and the build information of the binary:
+e.g. ./generated-binary --version
+{{- if .HasCompletion }}
+To enable the shell completion source the output of the completion command (bash, zsh or fish):
+e.g. source <(./generated-binary completion bash)
+{{- end }}
 ` + "`" + `
 	fmt.Fprint(stream, helpMsg)	
 }`))
@@ -245,10 +271,14 @@ type helpFlagData struct {
 	Name      string
 	ShortName *string
 	Type      string
+	EnvVar    string
+	Choices   []string
 }
 
 type tmplFuncHelpFunctionInput struct {
 	FunctionName  string
+	CommandName   string
+	Usage         string
 	Flags         []helpFlagData
 	RequiredFlags []helpFlagData
 }
@@ -256,53 +286,174 @@ type tmplFuncHelpFunctionInput struct {
 var tmplFuncHelpFunction = template.Must(template.New("FuncHelpFunction").
 	Parse(`
 func gosif_Show{{.FunctionName}}Help(stream *os.File) {
-	helpMsg := ` + "`" + `Function {{.FunctionName}}
+	helpMsg := ` + "`" + `Function {{.CommandName}}
+	Usage: {{.Usage}}
 	Required options:
 		{{- range $flag := .RequiredFlags }}
-		{{if $flag.ShortName}} -{{$flag.ShortName}} /{{end}} --{{$flag.Name | printf "%-10s"}}{{$flag.Type}}
+		{{if $flag.ShortName}} -{{$flag.ShortName}} /{{end}} --{{$flag.Name | printf "%-10s"}}{{$flag.Type}}{{if $flag.Choices}} {{"{"}}{{range $i, $c := $flag.Choices}}{{if $i}}|{{end}}{{$c}}{{end}}{{"}"}}{{end}}{{if $flag.EnvVar}} [env: {{$flag.EnvVar}}]{{end}}
 		{{- end }}
 	Available options:
 		{{- range $flag := .Flags }}
-		{{if $flag.ShortName}} -{{$flag.ShortName}} /{{end}} --{{$flag.Name | printf "%-10s"}}{{$flag.Type}}
+		{{if $flag.ShortName}} -{{$flag.ShortName}} /{{end}} --{{$flag.Name | printf "%-10s"}}{{$flag.Type}}{{if $flag.Choices}} {{"{"}}{{range $i, $c := $flag.Choices}}{{if $i}}|{{end}}{{$c}}{{end}}{{"}"}}{{end}}{{if $flag.EnvVar}} [env: {{$flag.EnvVar}}]{{end}}
 		{{- end }}
 ` + "`" + `
 	fmt.Fprint(stream, helpMsg)
 }`))
 
 type tmplParseFlagsFuncInput struct {
-	FuncFlags     []types.Flag
-	RequiredFlags []types.Flag
-	Cases         []string
-	FunctionName  string
+	FlagAliases       map[string]string
+	SliceFlags        []string
+	BoolFlags         []string
+	CountFlags        []string
+	LastWinsFlags     []string
+	Negations         map[string]string
+	Env               map[string]string
+	Choices           map[string][]string
+	Positional        []string
+	LeadingPositional bool
+	CollectRest       bool
+	RequiredFlags     []types.Flag
+	Cases             []string
+	FunctionName      string
+	CommandNames      []string
+	// Interactive turns on the prompts for the missing required flags
+	// without the --interactive option
+	Interactive bool
+	Prompts     []tmplPromptInput
+}
+
+// tmplPromptInput describes a required flag that is prompted for in the
+// interactive mode.
+type tmplPromptInput struct {
+	// Name is the name of the flag on the command line
+	Name string
+	// Param is the name of the function parameter that the flag is parsed
+	// into
+	Param       string
+	Type        string
+	Description string
+	Choices     []string
+	IsSlice     bool
 }
 
 var tmplParseFlagsFunc = template.Must(tmplRunScriptFuncName.New("ParseFlagsFunc").Parse(`
 func {{template "ParseFlagsFuncName" .}}(args []string) (*{{template "FuncFlagsStructName" .}}, error) {
-	funcFlags := map[string]struct{}{
-		{{ range $flag := .FuncFlags -}}
-		"{{$flag.Name}}": {},
-		{{ end -}}
+	argsSpec := &gosif_ArgsSpec{
+		Flags: map[string]string{
+			{{ range $alias, $name := .FlagAliases -}}
+			"{{$alias}}": "{{$name}}",
+			{{ end -}}
+		},
+		{{- if .Positional }}
+		Positional: []string{ {{- range $i, $name := .Positional}}{{if $i}}, {{end}}"{{$name}}"{{end -}} },
+		{{- end }}
+		{{- if .LeadingPositional }}
+		LeadingPositional: true,
+		{{- end }}
+		{{- if .CollectRest }}
+		CollectRest: true,
+		{{- end }}
+		{{- if .SliceFlags }}
+		SliceFlags: map[string]bool{
+			{{ range $name := .SliceFlags -}}
+			"{{$name}}": true,
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .BoolFlags }}
+		BoolFlags: map[string]bool{
+			{{ range $name := .BoolFlags -}}
+			"{{$name}}": true,
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .CountFlags }}
+		CountFlags: map[string]bool{
+			{{ range $name := .CountFlags -}}
+			"{{$name}}": true,
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .LastWinsFlags }}
+		LastWinsFlags: map[string]bool{
+			{{ range $name := .LastWinsFlags -}}
+			"{{$name}}": true,
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .Negations }}
+		Negations: map[string]string{
+			{{ range $negated, $name := .Negations -}}
+			"{{$negated}}": "{{$name}}",
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .Env }}
+		Env: map[string]string{
+			{{ range $name, $envVar := .Env -}}
+			"{{$name}}": "{{$envVar}}",
+			{{ end -}}
+		},
+		{{- end }}
+		{{- if .Choices }}
+		Choices: map[string][]string{
+			{{ range $name, $choices := .Choices -}}
+			"{{$name}}": { {{- range $i, $c := $choices}}{{if $i}}, {{end}}"{{$c}}"{{end -}} },
+			{{ end -}}
+		},
+		{{- end }}
+		Commands: []string{ {{- range $i, $name := .CommandNames}}{{if $i}}, {{end}}"{{$name}}"{{end -}} },
+		Config: gosif_ConfigPath,
 	}
-	parsedArgs, err := gosif_ReadArgs(args, funcFlags)
+	parsedArgs, err := gosif_ReadArgs(args, argsSpec)
 	if err != nil {
 		return nil, err
 	}
 	{{ if ne (len .RequiredFlags) 0 -}}
 	requiredFlags := map[string]bool{
 		{{ range $flag := .RequiredFlags -}}
-		"{{$flag.Name}}": false,
+		"{{$flag.CLIName}}": false,
 		{{ end -}}
 	}
 	{{- end }}
 	flags := &{{template "FuncFlagsStructName" .}}{}
-	for name, parsedFlag := range parsedArgs {
+	parseFlag := func(name string, parsedFlag gosif_ReadFlag) (*{{template "FuncFlagsStructName" .}}, error) {
 		switch name {
 			{{- range $case := .Cases}}{{$case}}{{end}}
 		default:
 			return nil, fmt.Errorf("internal error: a flag %s was expected, but no treating case had been generated", name)
 		}
+		return flags, nil
+	}
+	for name, parsedFlag := range parsedArgs {
+		if _, err := parseFlag(name, parsedFlag); err != nil {
+			return nil, err
+		}
 	}
 	{{- if ne (len .RequiredFlags) 0 }}
+	if gosif_UtilShouldPrompt({{.Interactive}}) {
+		prompts := []gosif_Prompt{
+			{{- range $p := .Prompts}}
+			{Name: "{{$p.Name}}", Param: "{{$p.Param}}", PassedFlag: "--{{$p.Name}}", Type: "{{$p.Type}}"
+				{{- if $p.Description}}, Description: {{printf "%q" $p.Description}}{{end}}
+				{{- if $p.Choices}}, Choices: []string{ {{- range $i, $c := $p.Choices}}{{if $i}}, {{end}}"{{$c}}"{{end -}} }{{end}}
+				{{- if $p.IsSlice}}, IsSlice: true{{end -}} },
+			{{- end}}
+		}
+		for _, p := range prompts {
+			for !requiredFlags[p.Name] {
+				parsedFlag, err := gosif_UtilPrompt(gosif_PromptReader, os.Stderr, p)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := parseFlag(p.Param, parsedFlag); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+					continue
+				}
+				gosif_Prompted = append(gosif_Prompted, parsedFlag)
+			}
+		}
+	}
 	if err := gosif_CheckRequiredFlags(requiredFlags); err != nil {
 		return nil, err
 	}
@@ -318,7 +469,10 @@ var tmplRunScriptFuncName = template.Must(tmplParseLoopName.New("RunScriptFuncNa
 
 type funcFlagStructureTmplInput struct {
 	FunctionName string
+	Callee       string
 	Flags        []types.Flag
+	// Call are the statements calling the function and returning its error
+	Call string
 }
 
 var tmplFuncFlagsStruct = template.Must(tmplRunScriptFuncName.New("FuncFlagsStruct").Parse(`
@@ -328,100 +482,6 @@ type {{template "FuncFlagsStructName" .}} struct {
 	{{ end -}}
 }`))
 
-type tmplFlagCasePrefixInput struct {
-	ArgName string
-	ArgType string
-}
-
-var tmplFlagCasePrefix = template.Must(tmplCastFunctionName.New("FlagCasePrefix").Parse(`
-case "{{.ArgName}}":`))
-
-var tmplFlagCaseParsingErrCheck = template.Must(tmplFlagCasePrefix.New("FlagCaseParsingErrCheck").Parse(`
-if err != nil {
-	return nil, fmt.Errorf("flag %s: %v", parsedFlag.PassedFlag, err)
-}`))
-
-var tmplFlagCasePostfix = template.Must(tmplFlagCaseParsingErrCheck.New("FlagCasePostfix").Parse(`
-if err != nil {
-	return nil, fmt.Errorf("flag %s: %v", parsedFlag.PassedFlag, err)
-}
-flags.{{.ArgName}} = val
-{{- if not (or .IsPointer (eq .CoreType "bool") ) }}
-	requiredFlags["{{.ArgName}}"] = true
-{{- end }}`))
-
-type tmplFlagCaseInput struct {
-	Prefix  string
-	Body    string
-	Postfix string
-}
-
-var tmplFlagCase = template.Must(template.New("FlagCase").
-	Parse(`{{.Prefix}}
-{{.Body}}
-{{.Postfix}}`))
-
-type simpleArgCaseTmplInput struct {
-	ArgName   string
-	ArgType   string
-	CoreType  string
-	IsPointer bool
-}
-
-var tmplBoolArgCase = template.Must(tmplFlagCasePostfix.New("BoolArgCase").
-	Parse(`{{template "FlagCasePrefix" .}}
-{{template "FlagCaseZeroOrSingleArgCheck" .}}
-if len(parsedFlag.Args) == 0 {
-	{{if .IsPointer}}*{{end}}val = true
-} else {
-	val, err = {{template "CastFunctionName" .}}(parsedFlag.Args[0])
-}
-{{- template "FlagCasePostfix" .}}`))
-
-var tmplRuneArgCase = template.Must(tmplFlagCasePostfix.New("RuneArgCase").
-	Parse(`{{template "FlagCasePrefix" .}}
-argRunes := []rune(arg)
-if len(argRunes) > 1 {
-	return val, fmt.Errorf("failed to cast %s to rune: %s contains more than one rune", arg)
-} else if len(argRunes) == 0 {
-	return val, fmt.Errorf("failed to cast %s to rune: %s is an empty string", arg)
-}
-val, err = {{template "CastFunctionName" .}}(argRunes[0])
-{{- template "FlagCasePostfix" .}}`))
-
-type tmplStringArgCastInput struct {
-	CastFunctionName string
-	Ampersands       string
-}
-
-var tmplStringArgCast = template.Must(template.New("StringArgCast").
-	Parse(`{{if .Ampersands}}directVal{{else}}val{{end}}, err := {{.CastFunctionName}}(arg)
-if err != nil {
-	return nil, fmt.Errorf("cast failed: %v", err)
-}
-{{if .Ampersands}}val := {{.Ampersands}}directVal{{end}}`))
-
-type newSliceCaseInput struct {
-	IsSlice       bool
-	ArrLength     int
-	ElemType      string
-	PrevLayer     string
-	ArrLayerIndex int
-}
-
-type tmplNewFlagCasePostfixInput struct {
-	IsRequired         bool
-	Ampersands         string
-	ReturnValueVarName string
-	ArgName            string
-}
-
-var tmplNewFlagCasePostfix = template.Must(tmplFlagCaseParsingErrCheck.New("NewFlagCasePostfix").
-	Parse(`flags.{{.ArgName}} = {{.Ampersands}}{{.ReturnValueVarName}}
-{{- if .IsRequired }}
-	requiredFlags["{{.ArgName}}"] = true
-{{- end }}`))
-
 type castFunctionBaseInput tmplCastFunctionNameInput
 
 var tmplCastFunctionPrefix = template.Must(tmplCastFunctionName.New("CastFunctionPrefix").Parse(`
@@ -435,12 +495,13 @@ return val, err
 
 var tmplCastFunctionBool = template.Must(tmplCastFunctionPostfix.New("CastFunctionBool").
 	Parse(`{{template "CastFunctionPrefix" .}}
-lowerParsedArg := strings.ToLower(arg)
-if lowerParsedArg != "true" && lowerParsedArg != "t" && lowerParsedArg != "false" && lowerParsedArg != "f" {
-	return val, fmt.Errorf("expected zero or one argument that must be any of [true, t, false, f] (case insensitive), got: %s", arg)
-}
-if lowerParsedArg == "true" || lowerParsedArg == "t" {
+switch strings.ToLower(arg) {
+case "true", "t", "yes", "on", "1":
 	val = true
+case "false", "f", "no", "off", "0":
+	val = false
+default:
+	return val, fmt.Errorf("expected zero or one argument that must be any of [true, t, yes, on, 1, false, f, no, off, 0] (case insensitive), got: %s", arg)
 }
 {{- template "CastFunctionPostfix" .}}`))
 
@@ -470,7 +531,7 @@ var tmplCastFunctionNum = template.Must(tmplCastFunctionPostfix.New("CastFunctio
 	{{- $castExpr = printf "(%s)(%s)" $castType $varName -}}
 {{- end }}
 if err != nil {
-	return val, fmt.Errorf("failed to cast %s to {{$castType}}: %v", arg, err)
+	return val, fmt.Errorf("failed to cast %s to {{$castType}}: %w", arg, err)
 }
 val = {{$castExpr}}
 {{- template "CastFunctionPostfix" .}}`))
@@ -486,7 +547,7 @@ var tmplCastFunctionFloat = template.Must(tmplCastFunctionPostfix.New("CastFunct
 	Parse(`{{template "CastFunctionPrefix" .}}
 valFloat, err := strconv.ParseFloat(arg, {{.BitSize}})
 if err != nil {
-	return val, fmt.Errorf("failed to cast \"%s\" to float{{.BitSize}}: %v", arg, err)
+	return val, fmt.Errorf("failed to cast \"%s\" to float{{.BitSize}}: %w", arg, err)
 }
 val = {{if eq .BitSize "32"}}(float32)(valFloat){{else}}valFloat{{end}}
 {{- template "CastFunctionPostfix" .}}`))
@@ -494,16 +555,26 @@ val = {{if eq .BitSize "32"}}(float32)(valFloat){{else}}valFloat{{end}}
 type runScriptFuncTmplInput funcFlagStructureTmplInput
 
 var tmplRunScriptFunc = template.Must(tmplRunScriptFuncName.New("RunScriptFunc").Parse(`
-func {{template "RunScriptFuncName" .}}(flags *{{template "FuncFlagsStructName" .}}) {
-	{{.FunctionName}}({{range $i, $flag := .Flags}}{{if $i}},{{end}}flags.{{$flag.Name}}{{end}})
+func {{template "RunScriptFuncName" .}}(flags *{{template "FuncFlagsStructName" .}}) error {
+	return gosif_UtilRun(func() error {
+		{{.Call}}
+	})
 }`))
 
 type mainFuncScriptCaseTmplInput struct {
 	FunctionName string
+	CommandNames []string
+	Callee       string
+	// Call are the statements calling the function without arguments and
+	// returning its error
+	Call string
 }
 
-var tmplMainFuncScriptCase = template.Must(tmplRunScriptFuncName.New("MainFuncScriptCase").Parse(`
-case "{{.FunctionName}}":
+var tmplMainFuncCaseNames = template.Must(tmplRunScriptFuncName.New("MainFuncCaseNames").Parse(
+	`{{range $i, $name := .CommandNames}}{{if $i}}, {{end}}"{{$name}}"{{end}}`))
+
+var tmplMainFuncScriptCase = template.Must(tmplMainFuncCaseNames.New("MainFuncScriptCase").Parse(`
+case {{template "MainFuncCaseNames" .}}:
 	if len(os.Args) == 3 && os.Args[2] == "help" {
 		gosif_Show{{.FunctionName}}Help(os.Stdout)
 		return
@@ -512,27 +583,90 @@ case "{{.FunctionName}}":
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
 		gosif_Show{{- .FunctionName }}Help(os.Stderr)
-		os.Exit(1)
+		os.Exit(gosif_ExitUsage)
 	}
-	{{template "RunScriptFuncName" .}}(flags)
-	os.Exit(0)`))
-
-var tmplMainFuncNoArgsScriptCase = template.Must(template.New("MainFuncScriptCase").Parse(`
-case "{{.FunctionName}}":
-	{{.FunctionName}}()
-	os.Exit(0)`))
+	gosif_UtilShowCommandLine(os.Stderr)
+	if err := {{template "RunScriptFuncName" .}}(flags); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
+		os.Exit(gosif_ExitFailure)
+	}
+	os.Exit(gosif_ExitOK)`))
+
+var tmplMainFuncNoArgsScriptCase = template.Must(tmplMainFuncCaseNames.New("MainFuncNoArgsScriptCase").Parse(`
+case {{template "MainFuncCaseNames" .}}:
+	gosif_UtilShowCommandLine(os.Stderr)
+	if err := gosif_UtilRun(func() error {
+		{{.Call}}
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
+		os.Exit(gosif_ExitFailure)
+	}
+	os.Exit(gosif_ExitOK)`))
 
 type mainFuncTmplInput struct {
 	Cases   []string
 	HasMain bool
-}
+	// HasConfig is set if a function takes parameters, their values can then
+	// be read from the file passed with the --config option and the missing
+	// ones can be prompted for with the --interactive option
+	HasConfig bool
+	// Commands are suggested when an unknown function name is passed, the
+	// configuration file sections are named after them
+	Commands []string
+	// ConfigKeys are the flags names of all the functions, the keys set
+	// outside of the configuration file sections are checked against them
+	ConfigKeys []string
+	// Menu turns on the menu shown if no function name is passed, without
+	// the --interactive option
+	Menu      bool
+	MenuItems []menuItem
+}
+
+// menuItem is a function listed in the menu.
+type menuItem struct {
+	Name    string
+	Summary string
+}
+
+// mainFuncImports are the packages used by the main function and the
+// functions generated for each exposed function.
+var mainFuncImports = []string{"fmt", "os"}
 
 var tmplMainFunc = template.Must(tmplRunScriptFuncName.New("MainFunc").Parse(`
 func {{if .HasMain}}gosif{{else}}main{{end}}() {
+	os.Args, gosif_Interactive = gosif_UtilExtractInteractive(os.Args)
+	{{- if .HasConfig }}
+	args, configPath, err := gosif_UtilExtractConfigPath(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
+		gosif_ShowScriptsHelp(os.Stderr)
+		os.Exit(gosif_ExitUsage)
+	}
+	os.Args = args
+	gosif_ConfigPath = configPath
+	gosif_ConfigSections = map[string]bool{ {{- range $i, $cmd := .Commands}}{{if $i}}, {{end}}{{printf "%q" $cmd}}: true{{end -}} }
+	gosif_ConfigKeys = map[string]bool{ {{- range $i, $key := .ConfigKeys}}{{if $i}}, {{end}}{{printf "%q" $key}}: true{{end -}} }
+	{{- end }}
+	{{- if .Menu }}
+	if len(os.Args) < 2 && gosif_UtilShouldPrompt(true) {
+		name, err := gosif_UtilMenu(gosif_PromptReader, os.Stderr, []gosif_MenuItem{
+			{{- range $item := .MenuItems}}
+			{Name: "{{$item.Name}}"{{if $item.Summary}}, Summary: {{printf "%q" $item.Summary}}{{end}}},
+			{{- end}}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
+			os.Exit(gosif_ExitUsage)
+		}
+		os.Args = append(os.Args, name)
+		gosif_Interactive = true
+		gosif_Menu = true
+	}
+	{{- end }}
 	if len(os.Args) < 2 {
 		fmt.Fprint(os.Stderr, "[ERR]: no function name passed\n")
 		gosif_ShowScriptsHelp(os.Stderr)
-		os.Exit(1)
+		os.Exit(gosif_ExitUsage)
 	}
 	if len(os.Args) == 2 && os.Args[1] == "help" {
 		gosif_ShowScriptsHelp(os.Stdout)
@@ -542,8 +676,11 @@ func {{if .HasMain}}gosif{{else}}main{{end}}() {
 		{{- range $case := .Cases}}{{$case}}{{end}}
 	default:
 		fmt.Fprintf(os.Stderr, "[ERR]: unknown function %s\n", os.Args[1])
+		if suggestion, ok := gosif_UtilSuggest(os.Args[1], []string{ {{- range $i, $cmd := .Commands}}{{if $i}}, {{end}}{{printf "%q" $cmd}}{{end -}} }); ok {
+			fmt.Fprintf(os.Stderr, "did you mean \"%s\"?\n", suggestion)
+		}
 		gosif_ShowScriptsHelp(os.Stderr)
-		os.Exit(1)
+		os.Exit(gosif_ExitUsage)
 	}
 }`))
 
@@ -554,17 +691,19 @@ type tmplFullFileInput struct {
 	CastFuncs       map[string]string
 	IndirFuncs      map[string]string
 	PredefinedFuncs map[string]string
+	Library         *libraryImport
 }
 
 var tmplFullFile = template.Must(template.New("FullFile").Parse(`
 package main
 
 import (
-	"fmt"
-	"os"
 	{{ range $import := .Imports -}}
 	"{{$import}}"
 	{{ end -}}
+	{{ if .Library -}}
+	{{.Library.Alias}} "{{.Library.Path}}"
+	{{ end -}}
 )
 
 {{.MainFunc}}
@@ -582,3 +721,188 @@ import (
 {{- range $predefinedFunc := .PredefinedFuncs }}
 {{ $predefinedFunc }}
 {{- end }}`))
+
+type tmplCompletionScriptInput struct {
+	Funcs  []completionFunc
+	Shells []string
+	// BuiltinCommands and BuiltinOptions are the builtin commands of the
+	// binary, e.g. version, and its builtin options without the leading
+	// dashes, e.g. version for --version
+	BuiltinCommands []string
+	BuiltinOptions  []string
+	HasConfig       bool
+}
+
+// The completion scripts refer to the binary as @PROG@ and to its name
+// sanitized for the shell identifiers as @IDENT@, both are replaced when the
+// script is printed.
+var tmplBashCompletion = template.Must(template.New("BashCompletion").Parse(`# bash completion for @PROG@
+_gosif_complete_@IDENT@() {
+	local cur prev cmd i
+	COMPREPLY=()
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[COMP_CWORD-1]}"
+	i=1
+	{{- if .HasConfig }}
+	if [ "$COMP_CWORD" -eq 2 ] && [ "$prev" = "--config" ]; then
+		COMPREPLY=( $(compgen -f -- "$cur") )
+		return
+	fi
+	if [ "${COMP_WORDS[1]}" = "--config" ]; then
+		i=3
+	fi
+	{{- end }}
+	if [ "$COMP_CWORD" -le "$i" ]; then
+		COMPREPLY=( $(compgen -W "help{{range $fn := .Funcs}} {{index $fn.Names 0}}{{end}}{{range .BuiltinCommands}} {{.}}{{end}}{{range .BuiltinOptions}} --{{.}}{{end}}{{if .HasConfig}} --config{{end}}" -- "$cur") )
+		return
+	fi
+	cmd="${COMP_WORDS[$i]}"
+	case "$cmd" in
+	completion)
+		COMPREPLY=( $(compgen -W "{{range $i, $shell := .Shells}}{{if $i}} {{end}}{{$shell}}{{end}}" -- "$cur") )
+		;;
+	{{- range $fn := .Funcs }}
+	{{range $i, $name := $fn.Names}}{{if $i}}|{{end}}{{$name}}{{end}})
+		{{- if $fn.Flags }}
+		case "$prev" in
+		{{- range $flag := $fn.Flags }}
+		{{- if $flag.TakesValue }}
+		{{$flag.Patterns}})
+			{{- if $flag.Completer }}
+			local IFS=$'\n'
+			COMPREPLY=( $(compgen -W "$("${COMP_WORDS[0]}" __complete "$cmd" "$prev" "$cur" 2>/dev/null)" -- "$cur") )
+			{{- else if $flag.Values }}
+			COMPREPLY=( $(compgen -W "{{range $i, $v := $flag.Values}}{{if $i}} {{end}}{{$v}}{{end}}" -- "$cur") )
+			{{- else if $flag.IsPath }}
+			COMPREPLY=( $(compgen -f -- "$cur") )
+			{{- end }}
+			return
+			;;
+		{{- end }}
+		{{- end }}
+		esac
+		{{- end }}
+		COMPREPLY=( $(compgen -W "help
+			{{- range $flag := $fn.Flags }}{{range $name := $flag.Names}} --{{$name}}{{end}}{{if $flag.ShortName}} -{{$flag.ShortName}}{{end}}{{end}}
+			{{- range $name := $fn.Negations }} --{{$name}}{{end}}" -- "$cur") )
+		;;
+	{{- end }}
+	esac
+}
+complete -F _gosif_complete_@IDENT@ @PROG@
+`))
+
+var tmplZshCompletion = template.Must(tmplBashCompletion.New("ZshCompletion").Parse(`# zsh completion for @PROG@, it relies on the bash completion emulation
+autoload -U +X compinit && compinit
+autoload -U +X bashcompinit && bashcompinit
+{{template "BashCompletion" .}}`))
+
+var tmplFishCompletion = template.Must(template.New("FishCompletion").Parse(`# fish completion for @PROG@
+complete -c @PROG@ -f
+complete -c @PROG@ -n "__fish_use_subcommand" -a "help{{range $fn := .Funcs}} {{index $fn.Names 0}}{{end}}{{range .BuiltinCommands}} {{.}}{{end}}"
+{{- range .BuiltinOptions }}
+complete -c @PROG@ -n "__fish_use_subcommand" -l {{.}}
+{{- end }}
+{{- if .HasConfig }}
+complete -c @PROG@ -n "__fish_use_subcommand" -l config -r -F
+{{- end }}
+complete -c @PROG@ -n "__fish_seen_subcommand_from completion" -a "{{range $i, $shell := .Shells}}{{if $i}} {{end}}{{$shell}}{{end}}"
+{{- range $fn := .Funcs }}
+{{- $cond := printf "__fish_seen_subcommand_from %s" (index $fn.Names 0) }}
+{{- range $i, $name := $fn.Names }}{{if $i}}{{$cond = printf "%s %s" $cond $name}}{{end}}{{end }}
+complete -c @PROG@ -n "{{$cond}}" -a "help"
+{{- range $flag := $fn.Flags }}
+complete -c @PROG@ -n "{{$cond}}"
+	{{- range $name := $flag.Names}} -l {{$name}}{{end}}
+	{{- with $flag.ShortName}} -{{if eq (len .) 1}}s{{else}}o{{end}} {{.}}{{end}}
+	{{- if $flag.TakesValue}} -r{{if $flag.Completer}} -a "(@PROG@ __complete {{index $fn.Names 0}} --{{index $flag.Names 0}} (commandline -ct) 2>/dev/null)"{{else if $flag.Values}} -a "{{range $i, $v := $flag.Values}}{{if $i}} {{end}}{{$v}}{{end}}"{{else if $flag.IsPath}} -F{{end}}{{end}}
+{{- end }}
+{{- range $name := $fn.Negations }}
+complete -c @PROG@ -n "{{$cond}}" -l {{$name}}
+{{- end }}
+{{- end }}
+`))
+
+type tmplCompletionFunctionInput struct {
+	Bash string
+	Zsh  string
+	Fish string
+}
+
+// completionImports are the packages used by tmplCompletionFunction.
+var completionImports = []string{"path/filepath", "strings"}
+
+var tmplCompletionFunction = template.Must(template.New("CompletionFunction").Parse(`
+const gosif_BashCompletion = ` + "`" + `{{.Bash}}` + "`" + `
+
+const gosif_ZshCompletion = ` + "`" + `{{.Zsh}}` + "`" + `
+
+const gosif_FishCompletion = ` + "`" + `{{.Fish}}` + "`" + `
+
+func gosif_ShowCompletion(stream *os.File, shell string) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = gosif_BashCompletion
+	case "zsh":
+		script = gosif_ZshCompletion
+	case "fish":
+		script = gosif_FishCompletion
+	default:
+		return fmt.Errorf("unsupported shell %s, expected bash, zsh or fish", shell)
+	}
+	prog := filepath.Base(os.Args[0])
+	ident := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, prog)
+	fmt.Fprint(stream, strings.NewReplacer("@PROG@", prog, "@IDENT@", ident).Replace(script))
+	return nil
+}`))
+
+var tmplCompleteFunction = template.Must(template.New("CompleteFunction").Parse(`
+func gosif_Complete(args []string) {
+	if len(args) != 3 {
+		return
+	}
+	var candidates []string
+	switch args[0] {
+	{{- range $fn := .Funcs }}
+	{{- $hasCompleters := false }}
+	{{- range $flag := $fn.Flags }}{{if $flag.Completer}}{{$hasCompleters = true}}{{end}}{{end}}
+	{{- if $hasCompleters }}
+	case {{range $i, $name := $fn.Names}}{{if $i}}, {{end}}"{{$name}}"{{end}}:
+		switch args[1] {
+		{{- range $flag := $fn.Flags }}
+		{{- if $flag.Completer }}
+		case {{$flag.Quoted}}:
+			candidates = {{$flag.Completer}}(args[2])
+		{{- end }}
+		{{- end }}
+		}
+	{{- end }}
+	{{- end }}
+	}
+	for _, candidate := range candidates {
+		fmt.Println(candidate)
+	}
+}`))
+
+var tmplMainFuncCompleteCase = template.Must(template.New("MainFuncCompleteCase").Parse(`
+case "__complete":
+	gosif_Complete(os.Args[2:])
+	os.Exit(gosif_ExitOK)`))
+
+var tmplMainFuncCompletionCase = template.Must(template.New("MainFuncCompletionCase").Parse(`
+case "completion":
+	if len(os.Args) != 3 {
+		fmt.Fprint(os.Stderr, "[ERR]: expected a shell name: bash, zsh or fish\n")
+		os.Exit(gosif_ExitUsage)
+	}
+	if err := gosif_ShowCompletion(os.Stdout, os.Args[2]); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERR]: %v\n", err)
+		os.Exit(gosif_ExitUsage)
+	}
+	os.Exit(gosif_ExitOK)`))
@@ -4,7 +4,8 @@ import (
 	"fmt"
 )
 
-// TODO(SSH): cover with tests
+// GetShortNames returns the shortest unique prefixes of the names, a name
+// gets no short name if it is a prefix of another name.
 func GetShortNames(names []string) (map[string]*string, error) {
 	if len(names) == 0 {
 		return nil, nil
@@ -0,0 +1,138 @@
+package trie
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetShortNames(t *testing.T) {
+	strPtr := func(s string) *string {
+		return &s
+	}
+	okCases := []struct {
+		in       []string
+		expected map[string]*string
+	}{
+		{
+			in: []string{"num"},
+			expected: map[string]*string{
+				"num": strPtr("n"),
+			},
+		},
+		{
+			in: []string{"a", "b"},
+			expected: map[string]*string{
+				"a": strPtr("a"),
+				"b": strPtr("b"),
+			},
+		},
+		{
+			in: []string{"n", "n8", "n16"},
+			expected: map[string]*string{
+				"n":   nil,
+				"n8":  strPtr("n8"),
+				"n16": strPtr("n1"),
+			},
+		},
+		{
+			in: []string{"s", "sp", "ps", "psp"},
+			expected: map[string]*string{
+				"s":   nil,
+				"sp":  strPtr("sp"),
+				"ps":  nil,
+				"psp": strPtr("psp"),
+			},
+		},
+		{
+			in: []string{"max-retries", "max-size", "dry-run"},
+			expected: map[string]*string{
+				"max-retries": strPtr("max-r"),
+				"max-size":    strPtr("max-s"),
+				"dry-run":     strPtr("d"),
+			},
+		},
+		{
+			in: []string{"verbose", "version", "v"},
+			expected: map[string]*string{
+				"verbose": strPtr("verb"),
+				"version": strPtr("vers"),
+				"v":       nil,
+			},
+		},
+		{
+			in: []string{"héllo", "hélp"},
+			expected: map[string]*string{
+				"héllo": strPtr("héll"),
+				"hélp":  strPtr("hélp"),
+			},
+		},
+		{
+			in:       nil,
+			expected: nil,
+		},
+	}
+	for i, tc := range okCases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("ok test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual, err := GetShortNames(tc.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := eqShortNames(actual, tc.expected); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	errorCases := []struct {
+		in       []string
+		expected error
+	}{
+		{
+			in:       []string{"a", "a"},
+			expected: fmt.Errorf("failed to append the argument a: it is already in the trie"),
+		},
+		{
+			in:       []string{"a", ""},
+			expected: fmt.Errorf("passed name is empty"),
+		},
+	}
+	for i, tc := range errorCases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("error test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			actual, err := GetShortNames(tc.in)
+			if err == nil {
+				t.Fatalf("expected an error, but short names %v were returned", actual)
+			}
+			if err.Error() != tc.expected.Error() {
+				t.Fatalf("actual and expected errors are not equal:\n\tactual: %v\n\texpected: %v", err, tc.expected)
+			}
+		})
+	}
+}
+
+func eqShortNames(actual map[string]*string, expected map[string]*string) error {
+	if len(actual) != len(expected) {
+		return fmt.Errorf("actual map %v (length %d) and expected map %v (length %d) are of different length", actual, len(actual), expected, len(expected))
+	}
+	for name, expectedShort := range expected {
+		actualShort, ok := actual[name]
+		if !ok {
+			return fmt.Errorf("actual map does not contain the name %s", name)
+		}
+		if expectedShort == nil && actualShort == nil {
+			continue
+		}
+		if expectedShort == nil {
+			return fmt.Errorf("expected no short name for %s, got %s", name, *actualShort)
+		}
+		if actualShort == nil {
+			return fmt.Errorf("expected the short name %s for %s, got none", *expectedShort, name)
+		}
+		if *actualShort != *expectedShort {
+			return fmt.Errorf("expected the short name %s for %s, got %s", *expectedShort, name, *actualShort)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,16 @@
 package types
 
 type Flag struct {
-	Name      string
+	Name string
+	// CLIName is the name of the flag on the command line, it differs from
+	// the parameter name in the kebab-case mode
+	CLIName   string
 	ShortName *string
 	Type      string
-}
\ No newline at end of file
+	// EnvVar is the environment variable that is read if the flag is not
+	// passed, the flag has no fallback if it is empty
+	EnvVar string
+	// Choices are the values accepted by the flag, any value is accepted if
+	// it is empty
+	Choices []string
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"runtime/debug"
+	"text/template"
+)
+
+// versionCommand is the builtin command that prints the build information,
+// it is also available as the --version option.
+const versionCommand = "version"
+
+// gosifModule is the module path of gosif.
+const gosifModule = "github.com/SergeyShpak/gosif"
+
+// Version is the gosif version recorded in the generated code, it is read
+// from the build information unless it is set with
+// -ldflags "-X github.com/SergeyShpak/gosif/generator.Version=v1.2.3".
+var Version string
+
+// generatorVersion returns the version of gosif that generates the code.
+func generatorVersion() string {
+	if len(Version) != 0 {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == gosifModule && len(info.Main.Version) != 0 {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == gosifModule {
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// versionImports are the packages used by tmplVersionFunction.
+var versionImports = []string{"runtime", "runtime/debug"}
+
+var tmplVersionFunction = template.Must(template.New("VersionFunction").Parse(`
+// The build information shown by the version command can be overridden with
+// -ldflags "-X main.gosif_Version=v1.2.3 -X main.gosif_Revision=abc123 -X main.gosif_Modified=false".
+var (
+	gosif_Version  string
+	gosif_Revision string
+	gosif_Modified string
+)
+
+const gosif_GeneratorVersion = {{printf "%q" .}}
+
+func gosif_ShowVersion(stream *os.File) {
+	module, version, revision, modified := "unknown", "unknown", "unknown", "unknown"
+	goVersion := runtime.Version()
+	if info, ok := debug.ReadBuildInfo(); ok {
+		module, version = info.Main.Path, info.Main.Version
+		goVersion = info.GoVersion
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.modified":
+				modified = s.Value
+			}
+		}
+	}
+	for _, override := range []struct {
+		value *string
+		set   string
+	}{
+		{&version, gosif_Version},
+		{&revision, gosif_Revision},
+		{&modified, gosif_Modified},
+	} {
+		if len(override.set) != 0 {
+			*override.value = override.set
+		}
+	}
+	fmt.Fprintf(stream, "module:   %s\n", module)
+	fmt.Fprintf(stream, "version:  %s\n", version)
+	fmt.Fprintf(stream, "revision: %s\n", revision)
+	fmt.Fprintf(stream, "modified: %s\n", modified)
+	fmt.Fprintf(stream, "go:       %s\n", goVersion)
+	fmt.Fprintf(stream, "gosif:    %s\n", gosif_GeneratorVersion)
+}
+`))
+
+type tmplMainFuncVersionCaseInput struct {
+	// HasCommand is unset if a function is exposed under the version command
+	// name, only the --version option is then handled
+	HasCommand bool
+}
+
+var tmplMainFuncVersionCase = template.Must(template.New("MainFuncVersionCase").Parse(`
+case {{if .HasCommand}}"version", {{end}}"--version":
+	gosif_ShowVersion(os.Stdout)
+	os.Exit(gosif_ExitOK)`))
+
+// generateVersionFunction generates the function printing the build
+// information of the binary.
+func generateVersionFunction() (string, error) {
+	return generateFromTemplate(tmplVersionFunction, generatorVersion())
+}
@@ -1,19 +1,74 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/SergeyShpak/gosif/generator"
 )
 
 func main() {
-	if len(os.Args) < 2 {
+	pkgDir := flag.String("pkg", "", "a non-main package whose exported functions should be exposed as commands")
+	outDir := flag.String("o", "", "a directory to put the generated main package for the -pkg package, or the documentation, to")
+	tags := flag.String("tags", "", "a comma-separated list of build tags to consider satisfied while parsing the package")
+	kebab := flag.Bool("kebab", false, "convert the commands and flags names to the kebab case (e.g. --max-retries for maxRetries)")
+	envPrefix := flag.String("env", "", "an application name that turns on the environment variables fallback for the flags, the variables are named <APP>_<FUNC>_<FLAG>")
+	docs := flag.String("docs", "", fmt.Sprintf("generate the documentation of the commands in the given format (%s) to the -o directory instead of the code", strings.Join(generator.DocsFormats(), ", ")))
+	name := flag.String("name", "", "the name of the binary in the documentation, the package directory name by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n\tgosif <dir>\n\tgosif -pkg <package dir> -o <output dir>\n\tgosif -docs <format> -o <output dir> <dir>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	opts := &generator.Options{
+		BuildTags: splitTags(*tags),
+		KebabCase: *kebab,
+		EnvPrefix: *envPrefix,
+		ProgName:  *name,
+	}
+	if len(*docs) != 0 {
+		dir := *pkgDir
+		if len(dir) == 0 {
+			dir = flag.Arg(0)
+		}
+		if len(dir) == 0 || len(*outDir) == 0 {
+			log.Println("[ERR]: a package directory and an output directory set with -o are required when -docs is used")
+			os.Exit(1)
+		}
+		if err := generator.GenerateDocs(dir, *outDir, *docs, opts); err != nil {
+			log.Println("[ERR]: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(*pkgDir) != 0 {
+		if len(*outDir) == 0 {
+			log.Println("[ERR]: an output directory must be set with -o when -pkg is used")
+			os.Exit(1)
+		}
+		if err := generator.GenerateScriptsForPackage(*pkgDir, *outDir, opts); err != nil {
+			log.Println("[ERR]: ", err)
+			os.Exit(1)
+		}
 		return
 	}
-	scriptsDir := os.Args[1]
-	if err := generator.GenerateScriptsForDir(scriptsDir); err != nil {
+	if flag.NArg() < 1 {
+		return
+	}
+	scriptsDir := flag.Arg(0)
+	if err := generator.GenerateScriptsForDir(scriptsDir, opts); err != nil {
 		log.Println("[ERR]: ", err)
 		os.Exit(1)
 	}
 }
+
+// splitTags splits the value of the -tags option, accepting both the comma-
+// and the space-separated forms, as go build does.
+func splitTags(tags string) []string {
+	return strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"go/ast"
+	"strings"
+)
+
+const directivePrefix = "//gosif:"
+
+// Directive is a "//gosif:<name> <args>" comment that tweaks the generated
+// code.
+type Directive struct {
+	Name string
+	// Args is the text that follows the directive name
+	Args string
+}
+
+// Fields splits the directive arguments around spaces.
+func (d *Directive) Fields() []string {
+	return strings.Fields(d.Args)
+}
+
+func parseDirectives(groups ...*ast.CommentGroup) []*Directive {
+	directives := make([]*Directive, 0)
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, c := range g.List {
+			if !strings.HasPrefix(c.Text, directivePrefix) {
+				continue
+			}
+			text := strings.TrimSpace(c.Text[len(directivePrefix):])
+			if len(text) == 0 {
+				continue
+			}
+			d := &Directive{
+				Name: text,
+			}
+			if sepPos := strings.IndexAny(text, " \t"); sepPos != -1 {
+				d.Name = text[:sepPos]
+				d.Args = strings.TrimSpace(text[sepPos+1:])
+			}
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// parseDoc returns the text of the doc comments without the directives.
+func parseDoc(groups ...*ast.CommentGroup) string {
+	texts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		withoutDirectives := &ast.CommentGroup{}
+		for _, c := range g.List {
+			if !strings.HasPrefix(c.Text, directivePrefix) {
+				withoutDirectives.List = append(withoutDirectives.List, c)
+			}
+		}
+		if text := strings.TrimSpace(withoutDirectives.Text()); len(text) != 0 {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+func filterDirectives(directives []*Directive, name string) []*Directive {
+	filtered := make([]*Directive, 0)
+	for _, d := range directives {
+		if d.Name == name {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// packageComments returns the comments of the file that are neither attached
+// to a declaration nor placed inside of it, e.g. the comment above the package
+// clause, the package directives are only read from them.
+func packageComments(f *ast.File) []*ast.CommentGroup {
+	comments := make([]*ast.CommentGroup, 0, len(f.Comments))
+	for _, g := range f.Comments {
+		attached := false
+		for _, decl := range f.Decls {
+			var doc *ast.CommentGroup
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				doc = d.Doc
+			case *ast.GenDecl:
+				doc = d.Doc
+			}
+			if g == doc || (g.Pos() >= decl.Pos() && g.End() <= decl.End()) {
+				attached = true
+				break
+			}
+		}
+		if !attached {
+			comments = append(comments, g)
+		}
+	}
+	return comments
+}
+
+// HasDirective reports whether the package contains the directive name.
+func (p *PackageFunctions) HasDirective(name string) bool {
+	return len(filterDirectives(p.Directives, name)) != 0
+}
+
+// Directive returns the last directive name of the package, or nil if there
+// is no such directive.
+func (p *PackageFunctions) Directive(name string) *Directive {
+	directives := filterDirectives(p.Directives, name)
+	if len(directives) == 0 {
+		return nil
+	}
+	return directives[len(directives)-1]
+}
+
+// Directive returns the last directive name of the function doc comment, or
+// nil if there is no such directive.
+func (f *PkgFunc) Directive(name string) *Directive {
+	directives := filterDirectives(f.Directives, name)
+	if len(directives) == 0 {
+		return nil
+	}
+	return directives[len(directives)-1]
+}
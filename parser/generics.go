@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"regexp"
+	"strings"
+)
+
+const instantiateDirective = "instantiate"
+
+// instantiation is a concrete version of a generic function requested with a
+// "//gosif:instantiate Func[T1, T2] as Name" directive.
+type instantiation struct {
+	FuncName string
+	TypeArgs []string
+	Name     string
+	used     bool
+}
+
+var instantiateDirectiveRegexp = regexp.MustCompile(`^(\w+)\s*\[(.+)\]\s+as\s+(\w+)$`)
+
+func parseInstantiations(directives []*Directive) map[string][]*instantiation {
+	instantiations := make(map[string][]*instantiation)
+	for _, d := range filterDirectives(directives, instantiateDirective) {
+		matches := instantiateDirectiveRegexp.FindStringSubmatch(d.Args)
+		if matches == nil {
+			log.Printf("[WARN]: ignoring the directive \"%s%s %s\": expected the format \"%s%s Func[T1, T2] as Name\"", directivePrefix, d.Name, d.Args, directivePrefix, instantiateDirective)
+			continue
+		}
+		typeArgs := strings.Split(matches[2], ",")
+		for i, arg := range typeArgs {
+			typeArgs[i] = strings.TrimSpace(arg)
+		}
+		inst := &instantiation{
+			FuncName: matches[1],
+			TypeArgs: typeArgs,
+			Name:     matches[3],
+		}
+		instantiations[inst.FuncName] = append(instantiations[inst.FuncName], inst)
+	}
+	return instantiations
+}
+
+func isGeneric(funcType *ast.FuncType) bool {
+	return funcType.TypeParams != nil && len(funcType.TypeParams.List) != 0
+}
+
+// typeArgsSubstitution maps the type parameters of the generic function to
+// the type arguments of the instantiation, checking that the type arguments
+// satisfy the type parameters constraints that are unions of types.
+func typeArgsSubstitution(funcType *ast.FuncType, inst *instantiation) (map[string]ast.Expr, error) {
+	subst := make(map[string]ast.Expr)
+	argIdx := 0
+	for _, field := range funcType.TypeParams.List {
+		terms := constraintTerms(field.Type)
+		for _, name := range field.Names {
+			if argIdx >= len(inst.TypeArgs) {
+				return nil, fmt.Errorf("expected %d type arguments, got %d", countTypeParams(funcType), len(inst.TypeArgs))
+			}
+			typeArg := inst.TypeArgs[argIdx]
+			argIdx++
+			argExpr, err := parser.ParseExpr(typeArg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the type argument \"%s\": %v", typeArg, err)
+			}
+			if terms != nil {
+				if _, ok := terms[types.ExprString(argExpr)]; !ok {
+					return nil, fmt.Errorf("the type argument %s does not satisfy the constraint %s of the type parameter %s", typeArg, types.ExprString(field.Type), name.Name)
+				}
+			}
+			subst[name.Name] = argExpr
+		}
+	}
+	if argIdx != len(inst.TypeArgs) {
+		return nil, fmt.Errorf("expected %d type arguments, got %d", argIdx, len(inst.TypeArgs))
+	}
+	return subst, nil
+}
+
+func countTypeParams(funcType *ast.FuncType) int {
+	count := 0
+	for _, field := range funcType.TypeParams.List {
+		count += len(field.Names)
+	}
+	return count
+}
+
+// constraintTerms returns the types listed in a union constraint (e.g.
+// int | ~float64), or nil if the constraint cannot be checked syntactically.
+func constraintTerms(constraint ast.Expr) map[string]struct{} {
+	terms := make(map[string]struct{})
+	var collect func(e ast.Expr) bool
+	collect = func(e ast.Expr) bool {
+		switch t := e.(type) {
+		case *ast.BinaryExpr:
+			if t.Op != token.OR {
+				return false
+			}
+			return collect(t.X) && collect(t.Y)
+		case *ast.UnaryExpr:
+			if t.Op != token.TILDE {
+				return false
+			}
+			return collect(t.X)
+		case *ast.ParenExpr:
+			return collect(t.X)
+		case *ast.Ident:
+			if _, ok := types.Universe.Lookup(t.Name).(*types.TypeName); !ok || t.Name == "any" || t.Name == "comparable" {
+				return false
+			}
+			terms[t.Name] = struct{}{}
+			return true
+		default:
+			return false
+		}
+	}
+	if !collect(constraint) {
+		return nil
+	}
+	return terms
+}
+
+// substituteTypeParams returns a copy of the type expression where the type
+// parameters are replaced with the type arguments.
+func substituteTypeParams(expr ast.Expr, subst map[string]ast.Expr) ast.Expr {
+	if len(subst) == 0 {
+		return expr
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if typeArg, ok := subst[t.Name]; ok {
+			return typeArg
+		}
+		return t
+	case *ast.StarExpr:
+		return &ast.StarExpr{
+			Star: t.Star,
+			X:    substituteTypeParams(t.X, subst),
+		}
+	case *ast.ArrayType:
+		return &ast.ArrayType{
+			Lbrack: t.Lbrack,
+			Len:    t.Len,
+			Elt:    substituteTypeParams(t.Elt, subst),
+		}
+	default:
+		return expr
+	}
+}
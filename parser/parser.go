@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -23,13 +26,32 @@ type Packages struct {
 	OtherPacakges []*PackageFunctions
 }
 
-func ParsePackagesFunctions(dir string) (*Packages, error) {
-	pkgs, err := parseFiles(dir)
+// Config tweaks the selection of the files that are parsed.
+type Config struct {
+	// BuildTags are the build tags that are considered satisfied in addition
+	// to the ones of the current GOOS and GOARCH
+	BuildTags []string
+	// ExcludedFiles are the names of the files that are never parsed
+	ExcludedFiles []string
+}
+
+func ParsePackagesFunctions(dir string, cfg *Config) (*Packages, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	pkgs, err := parseFiles(dir, cfg)
 	if err != nil {
 		return nil, err
 	}
 	packages := &Packages{}
-	for pkgDir, pkgsInDir := range pkgs {
+	pkgDirs := make([]string, 0, len(pkgs))
+	for pkgDir := range pkgs {
+		pkgDirs = append(pkgDirs, pkgDir)
+	}
+	sort.Strings(pkgDirs)
+	for _, pkgDir := range pkgDirs {
+		parsed := pkgs[pkgDir]
+		pkgsInDir := parsed.Packages
 		if len(pkgsInDir) > 1 {
 			log.Printf("%s: multiple packages per directory is not currently supported", pkgDir)
 			continue
@@ -38,45 +60,94 @@ func ParsePackagesFunctions(dir string) (*Packages, error) {
 			continue
 		}
 		for pkgName, pkg := range pkgsInDir {
-			if pkgName != "main" {
-				log.Printf("[WARN] ignoring package %s, only main is parsed", pkgName)
+			// a non-main package is only exposed if it is located in dir itself,
+			// the other ones are not parsed, since resolving their function
+			// variables type checks them
+			if pkgName != "main" && filepath.Clean(pkgDir) != filepath.Clean(dir) {
+				continue
+			}
+			if pkgName == "main" && packages.MainPackage != nil {
+				log.Printf("[WARN] ignoring the main package in %s, the main package in %s is already parsed", pkgDir, packages.MainPackage.PackageDir)
 				continue
 			}
-			pkgFuncs, err := getPackageFunctions(pkgDir, pkgName, pkg)
+			pkgFuncs, err := getPackageFunctions(pkgDir, pkgName, pkg, parsed.Fset)
 			if err != nil {
 				return nil, err
 			}
+			if pkgName != "main" {
+				packages.OtherPacakges = append(packages.OtherPacakges, pkgFuncs)
+				continue
+			}
 			packages.MainPackage = pkgFuncs
-			return packages, nil
 		}
 	}
 	return packages, nil
 }
 
+// FindPackage returns the parsed non-main package located in the directory dir.
+func (p *Packages) FindPackage(dir string) *PackageFunctions {
+	for _, pkg := range p.OtherPacakges {
+		if filepath.Clean(pkg.PackageDir) == filepath.Clean(dir) {
+			return pkg
+		}
+	}
+	return nil
+}
+
 func GetFileFunctions(path string) ([]*PkgFunc, error) {
 	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
 	if err != nil {
 		return nil, fmt.Errorf("parsing the file \"%s\" failed: %v", path, err)
 	}
 	fileName := filepath.Base(path)
-	funcs, err := getFunctionsFromFile(fileName, f)
+	funcs, err := getFunctionsFromFile(fileName, f, nil)
 	if err != nil {
 		return nil, fmt.Errorf("internal error: %v", err)
 	}
 	return funcs, nil
 }
 
-func parseFiles(dirPath string) (map[string]map[string]*ast.Package, error) {
-	result := make(map[string]map[string]*ast.Package)
+type parsedDir struct {
+	Fset     *token.FileSet
+	Packages map[string]*ast.Package
+}
+
+func parseFiles(dirPath string, cfg *Config) (map[string]*parsedDir, error) {
+	result := make(map[string]*parsedDir)
+	buildCtx := build.Default
+	buildCtx.BuildTags = append(buildCtx.BuildTags, cfg.BuildTags...)
+	excludedFiles := make(map[string]struct{}, len(cfg.ExcludedFiles))
+	for _, f := range cfg.ExcludedFiles {
+		excludedFiles[f] = struct{}{}
+	}
 	walkFn := func(path string, info os.FileInfo, err error) error {
 		if !info.IsDir() {
 			return nil
 		}
-		pkgs, err := parser.ParseDir(token.NewFileSet(), path, nil, parser.ParseComments)
+		filter := func(fi os.FileInfo) bool {
+			name := fi.Name()
+			if strings.HasSuffix(name, "_test.go") {
+				return false
+			}
+			if _, ok := excludedFiles[name]; ok {
+				return false
+			}
+			match, err := buildCtx.MatchFile(path, name)
+			if err != nil {
+				log.Printf("[WARN]: skipping the file %s: failed to evaluate its build constraints: %v", filepath.Join(path, name), err)
+				return false
+			}
+			return match
+		}
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, path, filter, parser.ParseComments)
 		if err != nil {
 			return err
 		}
-		result[path] = pkgs
+		result[path] = &parsedDir{
+			Fset:     fset,
+			Packages: pkgs,
+		}
 		return nil
 	}
 	if err := filepath.Walk(dirPath, walkFn); err != nil {
@@ -219,6 +290,24 @@ type PkgFunc struct {
 	Parameters []*FuncParam
 	IsExported bool
 	Path       string
+	// FuncName is the name of the called Go function, it differs from Name
+	// for the instantiations of generic functions
+	FuncName string
+	TypeArgs []string
+	// Directives are the gosif directives found in the function doc comment
+	Directives []*Directive
+	// Results are the types of the values returned by the function
+	Results []string
+	// Doc is the text of the function doc comment without the directives
+	Doc string
+}
+
+// Callee returns the expression that refers to the function from its package.
+func (f *PkgFunc) Callee() string {
+	if len(f.TypeArgs) == 0 {
+		return f.FuncName
+	}
+	return fmt.Sprintf("%s[%s]", f.FuncName, strings.Join(f.TypeArgs, ", "))
 }
 
 type PackageFunctions struct {
@@ -226,28 +315,71 @@ type PackageFunctions struct {
 	PackageName string
 	Functions   []*PkgFunc
 	HasMain     bool
+	// Directives are the gosif directives found in the comments of the
+	// package files that are not attached to a declaration
+	Directives []*Directive
 }
 
-func getPackageFunctions(pkgDir string, pkgName string, pkg *ast.Package) (*PackageFunctions, error) {
+func getPackageFunctions(pkgDir string, pkgName string, pkg *ast.Package, fset *token.FileSet) (*PackageFunctions, error) {
 	packageFunctions := &PackageFunctions{
 		PackageDir:  pkgDir,
 		PackageName: pkgName,
 	}
+	// the files are read in the order of their names, so that the function
+	// that wins a name clash and the last package directive do not change
+	// from run to run
+	fileNames := make([]string, 0, len(pkg.Files))
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	directives := make([]*Directive, 0)
+	pkgDirectives := make([]*Directive, 0)
+	for _, fileName := range fileNames {
+		f := pkg.Files[fileName]
+		directives = append(directives, parseDirectives(f.Comments...)...)
+		pkgDirectives = append(pkgDirectives, parseDirectives(packageComments(f)...)...)
+	}
+	packageFunctions.Directives = pkgDirectives
+	instantiations := parseInstantiations(directives)
 	funcs := make([]*PkgFunc, 0)
-	for fileName, f := range pkg.Files {
-		fileFuncs, err := getFunctionsFromFile(fileName, f)
+	untypedVars := make([]*funcVariable, 0)
+	for _, fileName := range fileNames {
+		f := pkg.Files[fileName]
+		fileFuncs, err := getFunctionsFromFile(fileName, f, instantiations)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get functions from file %s: %v", fileName, err)
 		}
 		funcs = append(funcs, fileFuncs...)
+		untypedVars = append(untypedVars, getUntypedVariablesFromFile(fileName, f)...)
+	}
+	if len(untypedVars) != 0 {
+		funcs = append(funcs, resolveFuncVariables(pkgDir, pkg, fset, untypedVars)...)
+	}
+	for _, insts := range instantiations {
+		for _, inst := range insts {
+			if !inst.used {
+				log.Printf("[WARN]: ignoring the instantiation %s of %s: no generic function %s found in the package %s", inst.Name, inst.FuncName, inst.FuncName, pkgName)
+			}
+		}
 	}
 	filteredFuncs := make([]*PkgFunc, 0, len(funcs))
+	seenNames := make(map[string]*PkgFunc, len(funcs))
 	for _, f := range funcs {
 		if f.Name == "main" {
 			packageFunctions.HasMain = true
 		}
+		if prev, ok := seenNames[f.Name]; ok {
+			log.Printf("[WARN]: skipping the function %s in the file %s: the name is already used by the function %s in the file %s", f.Callee(), f.Path, prev.Callee(), prev.Path)
+			continue
+		}
+		seenNames[f.Name] = f
 		if !f.IsExported {
-			log.Printf("[WARN]: skipping an unexported function %s in the file %s", f.Name, f.Path)
+			// unexported functions of a library package are its internals, there is
+			// no point in warning about them
+			if pkgName == "main" {
+				log.Printf("[WARN]: skipping an unexported function %s in the file %s", f.Name, f.Path)
+			}
 			continue
 		}
 		filteredFuncs = append(filteredFuncs, f)
@@ -256,7 +388,7 @@ func getPackageFunctions(pkgDir string, pkgName string, pkg *ast.Package) (*Pack
 	return packageFunctions, nil
 }
 
-func getFunctionsFromFile(fileName string, f *ast.File) ([]*PkgFunc, error) {
+func getFunctionsFromFile(fileName string, f *ast.File, instantiations map[string][]*instantiation) ([]*PkgFunc, error) {
 	if f == nil {
 		return nil, fmt.Errorf("the passed *ast.File is nil")
 	}
@@ -264,18 +396,34 @@ func getFunctionsFromFile(fileName string, f *ast.File) ([]*PkgFunc, error) {
 	for _, decl := range f.Decls {
 		switch funcDecl := decl.(type) {
 		case *ast.FuncDecl:
+			if funcDecl.Recv != nil {
+				continue
+			}
+			if isGeneric(funcDecl.Type) {
+				funcs = append(funcs, instantiateFunction(fileName, funcDecl, instantiations[funcDecl.Name.Name])...)
+				continue
+			}
 			pkgFunc := &PkgFunc{
 				Name:       funcDecl.Name.Name,
+				FuncName:   funcDecl.Name.Name,
 				IsExported: funcDecl.Name.IsExported(),
 				Path:       fileName,
+				Directives: parseDirectives(funcDecl.Doc),
+				Doc:        parseDoc(funcDecl.Doc),
 			}
-			parameters, err := parseFunction(funcDecl)
+			parameters, err := parseFunction(funcDecl.Type, nil)
 			if err != nil {
 				log.Printf("[WARN]: skipping the function %s in %s: %v", funcDecl.Name.Name, fileName, err)
 				continue
 			}
 			pkgFunc.Parameters = parameters
+			pkgFunc.Results = funcResults(funcDecl.Type, nil)
 			funcs = append(funcs, pkgFunc)
+		case *ast.GenDecl:
+			if funcDecl.Tok != token.VAR {
+				continue
+			}
+			funcs = append(funcs, getFuncVariablesFromDecl(fileName, funcDecl)...)
 		default:
 			continue
 		}
@@ -292,26 +440,86 @@ func (p FuncParam) IsAnArray() bool {
 	return len(p.Type.Layers) > 0
 }
 
-func parseFunction(decl *ast.FuncDecl) ([]*FuncParam, error) {
-	astParams := decl.Type.Params.List
+func instantiateFunction(fileName string, decl *ast.FuncDecl, instantiations []*instantiation) []*PkgFunc {
+	funcs := make([]*PkgFunc, 0, len(instantiations))
+	if len(instantiations) == 0 {
+		log.Printf("[WARN]: skipping the generic function %s in %s: functions with type parameters are not supported, a concrete version can be requested with a directive \"%s%s %s[...] as <Name>\"", decl.Name.Name, fileName, directivePrefix, instantiateDirective, decl.Name.Name)
+		return funcs
+	}
+	for _, inst := range instantiations {
+		inst.used = true
+		subst, err := typeArgsSubstitution(decl.Type, inst)
+		if err != nil {
+			log.Printf("[WARN]: skipping the instantiation %s of the generic function %s in %s: %v", inst.Name, decl.Name.Name, fileName, err)
+			continue
+		}
+		parameters, err := parseFunction(decl.Type, subst)
+		if err != nil {
+			log.Printf("[WARN]: skipping the instantiation %s of the generic function %s in %s: %v", inst.Name, decl.Name.Name, fileName, err)
+			continue
+		}
+		funcs = append(funcs, &PkgFunc{
+			Name:       inst.Name,
+			FuncName:   decl.Name.Name,
+			TypeArgs:   inst.TypeArgs,
+			IsExported: decl.Name.IsExported(),
+			Path:       fileName,
+			Parameters: parameters,
+			Directives: parseDirectives(decl.Doc),
+			Doc:        parseDoc(decl.Doc),
+			Results:    funcResults(decl.Type, subst),
+		})
+	}
+	return funcs
+}
+
+func parseFunction(funcType *ast.FuncType, typeArgs map[string]ast.Expr) ([]*FuncParam, error) {
+	astParams := funcType.Params.List
 	parameters := make([]*FuncParam, len(astParams))
 	for i, param := range astParams {
 		if len(param.Names) != 1 {
 			return nil, fmt.Errorf("cannot parse a parameter with %d names", len(param.Names))
 		}
-		paramType, err := extractParameterType(param.Type)
+		funcParam, err := newFuncParam(param.Names[0].Name, substituteTypeParams(param.Type, typeArgs))
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse the parameter \"%s\": %v", param.Names[0].Name, err)
+			return nil, err
 		}
-		if err := checkParamType(paramType); err != nil {
-			return nil, fmt.Errorf("failed to parse the parameter \"%s\": %v", param.Names[0].Name, err)
+		parameters[i] = funcParam
+	}
+	return parameters, nil
+}
+
+// funcResults returns the types of the values returned by a function.
+func funcResults(funcType *ast.FuncType, typeArgs map[string]ast.Expr) []string {
+	results := make([]string, 0)
+	if funcType.Results == nil {
+		return results
+	}
+	for _, field := range funcType.Results.List {
+		typeStr := types.ExprString(substituteTypeParams(field.Type, typeArgs))
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
 		}
-		parameters[i] = &FuncParam{
-			Name: param.Names[0].Name,
-			Type: paramType,
+		for i := 0; i < count; i++ {
+			results = append(results, typeStr)
 		}
 	}
-	return parameters, nil
+	return results
+}
+
+func newFuncParam(name string, typeExpr ast.Expr) (*FuncParam, error) {
+	paramType, err := extractParameterType(typeExpr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the parameter \"%s\": %v", name, err)
+	}
+	if err := checkParamType(paramType); err != nil {
+		return nil, fmt.Errorf("failed to parse the parameter \"%s\": %v", name, err)
+	}
+	return &FuncParam{
+		Name: name,
+		Type: paramType,
+	}, nil
 }
 
 func checkParamType(p *parameterType) error {
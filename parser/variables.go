@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"sort"
+)
+
+// funcVariable is an exported package-level variable whose type is not
+// spelled out in its declaration, e.g. var Cleanup = withRetry(cleanupOnce),
+// so that the package must be type checked to find out if it is a function.
+type funcVariable struct {
+	Ident      *ast.Ident
+	FileName   string
+	Directives []*Directive
+	Doc        string
+}
+
+// getFuncVariablesFromDecl returns the exported variables of the declaration
+// whose function type is known without type checking, i.e. the variables
+// declared with an explicit func(...) type or initialized with a function
+// literal.
+func getFuncVariablesFromDecl(fileName string, decl *ast.GenDecl) []*PkgFunc {
+	funcs := make([]*PkgFunc, 0)
+	for _, spec := range decl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			if !name.IsExported() {
+				continue
+			}
+			funcType := valueSpecFuncType(valueSpec, i)
+			if funcType == nil {
+				continue
+			}
+			parameters, err := parseFunction(funcType, nil)
+			if err != nil {
+				log.Printf("[WARN]: skipping the function variable %s in %s: %v", name.Name, fileName, err)
+				continue
+			}
+			funcs = append(funcs, &PkgFunc{
+				Name:       name.Name,
+				FuncName:   name.Name,
+				IsExported: name.IsExported(),
+				Path:       fileName,
+				Parameters: parameters,
+				Directives: parseDirectives(decl.Doc, valueSpec.Doc),
+				Doc:        parseDoc(decl.Doc, valueSpec.Doc),
+				Results:    funcResults(funcType, nil),
+			})
+		}
+	}
+	return funcs
+}
+
+func valueSpecFuncType(spec *ast.ValueSpec, nameIdx int) *ast.FuncType {
+	if spec.Type != nil {
+		funcType, _ := spec.Type.(*ast.FuncType)
+		return funcType
+	}
+	if len(spec.Values) != len(spec.Names) {
+		return nil
+	}
+	funcLit, ok := spec.Values[nameIdx].(*ast.FuncLit)
+	if !ok {
+		return nil
+	}
+	return funcLit.Type
+}
+
+func getUntypedVariablesFromFile(fileName string, f *ast.File) []*funcVariable {
+	vars := make([]*funcVariable, 0)
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if !name.IsExported() || !mayHoldFunction(valueSpec, i) {
+					continue
+				}
+				vars = append(vars, &funcVariable{
+					Ident:      name,
+					FileName:   fileName,
+					Directives: parseDirectives(genDecl.Doc, valueSpec.Doc),
+					Doc:        parseDoc(genDecl.Doc, valueSpec.Doc),
+				})
+			}
+		}
+	}
+	return vars
+}
+
+// mayHoldFunction reports whether the type of the variable can be a function
+// type that is only known after type checking.
+func mayHoldFunction(spec *ast.ValueSpec, nameIdx int) bool {
+	if spec.Type != nil {
+		switch spec.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			return true
+		default:
+			return false
+		}
+	}
+	if len(spec.Values) != len(spec.Names) {
+		return len(spec.Values) == 1
+	}
+	switch spec.Values[nameIdx].(type) {
+	case *ast.CallExpr, *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.ParenExpr, *ast.TypeAssertExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveFuncVariables type checks the package and returns the variables
+// that hold functions.
+func resolveFuncVariables(pkgDir string, pkg *ast.Package, fset *token.FileSet, vars []*funcVariable) []*PkgFunc {
+	fileNames := make([]string, 0, len(pkg.Files))
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	files := make([]*ast.File, 0, len(pkg.Files))
+	for _, fileName := range fileNames {
+		files = append(files, pkg.Files[fileName])
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	typesPkg, checkErr := conf.Check(pkgDir, fset, files, info)
+	funcs := make([]*PkgFunc, 0)
+	for _, v := range vars {
+		obj := info.Defs[v.Ident]
+		if obj == nil || obj.Type() == types.Typ[types.Invalid] {
+			if checkErr != nil {
+				log.Printf("[WARN]: skipping the variable %s in %s: failed to type check the package: %v", v.Ident.Name, v.FileName, checkErr)
+			}
+			continue
+		}
+		sig, ok := obj.Type().Underlying().(*types.Signature)
+		if !ok {
+			continue
+		}
+		parameters, err := signatureParameters(sig, typesPkg)
+		if err != nil {
+			log.Printf("[WARN]: skipping the function variable %s in %s: %v", v.Ident.Name, v.FileName, err)
+			continue
+		}
+		funcs = append(funcs, &PkgFunc{
+			Name:       v.Ident.Name,
+			FuncName:   v.Ident.Name,
+			IsExported: v.Ident.IsExported(),
+			Path:       v.FileName,
+			Parameters: parameters,
+			Directives: v.Directives,
+			Doc:        v.Doc,
+			Results:    signatureResults(sig, typesPkg),
+		})
+	}
+	return funcs
+}
+
+func signatureResults(sig *types.Signature, pkg *types.Package) []string {
+	results := make([]string, sig.Results().Len())
+	for i := range results {
+		results[i] = types.TypeString(sig.Results().At(i).Type(), types.RelativeTo(pkg))
+	}
+	return results
+}
+
+func signatureParameters(sig *types.Signature, pkg *types.Package) ([]*FuncParam, error) {
+	if sig.Variadic() {
+		return nil, fmt.Errorf("variadic functions are not supported")
+	}
+	params := sig.Params()
+	parameters := make([]*FuncParam, params.Len())
+	for i := 0; i < params.Len(); i++ {
+		p := params.At(i)
+		if len(p.Name()) == 0 || p.Name() == "_" {
+			return nil, fmt.Errorf("the parameter #%d is unnamed, but flag names are derived from the parameters names", i)
+		}
+		typeStr := types.TypeString(p.Type(), types.RelativeTo(pkg))
+		typeExpr, err := parser.ParseExpr(typeStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the type %s of the parameter \"%s\": %v", typeStr, p.Name(), err)
+		}
+		funcParam, err := newFuncParam(p.Name(), typeExpr)
+		if err != nil {
+			return nil, err
+		}
+		parameters[i] = funcParam
+	}
+	return parameters, nil
+}
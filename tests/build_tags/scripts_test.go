@@ -0,0 +1,106 @@
+//+build integration_tests
+
+package build_tags
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestBuildTags(t *testing.T) {
+	gosifBin := filepath.Join(t.TempDir(), "gosif")
+	cmd := exec.Command("go", "build", "-o", gosifBin, "github.com/SergeyShpak/gosif")
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to build gosif: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	testData := []struct {
+		// tags is the value of the gosif -tags option
+		tags string
+		// buildTags is the value of the go build -tags option
+		buildTags string
+		exposed   []string
+		unknown   []string
+	}{
+		{
+			tags:      "",
+			buildTags: "",
+			exposed:   []string{"PlainScript"},
+			unknown:   []string{"FooScript", "FooBarScript"},
+		},
+		{
+			tags:      "foo",
+			buildTags: "foo",
+			exposed:   []string{"PlainScript", "FooScript"},
+			unknown:   []string{"FooBarScript"},
+		},
+		{
+			tags:      "foo,bar",
+			buildTags: "foo,bar",
+			exposed:   []string{"PlainScript", "FooScript", "FooBarScript"},
+		},
+		{
+			tags:      "foo bar",
+			buildTags: "foo,bar",
+			exposed:   []string{"PlainScript", "FooScript", "FooBarScript"},
+		},
+	}
+	expectedOut := map[string]string{
+		"PlainScript":  "plain",
+		"FooScript":    "foo",
+		"FooBarScript": "foo bar",
+	}
+	for _, td := range testData {
+		td := td
+		t.Run(fmt.Sprintf("tags %q", td.tags), func(t *testing.T) {
+			utils.RemoveArtifacts(outBin, outDir)
+			cmd := exec.Command(gosifBin, "-tags", td.tags, outDir)
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("generation failed: %v", err)
+			}
+			cmd = exec.Command("go", "build", "-tags", td.buildTags, "-o", outBin)
+			cmd.Dir = outDir
+			cmd.Stderr = os.Stderr
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("build failed: %v", err)
+			}
+			cases := make([]utils.TestCase, 0, len(td.exposed)+len(td.unknown))
+			for _, s := range td.exposed {
+				cases = append(cases, utils.TestCase{
+					ScriptName:  s,
+					ExpectedOut: expectedOut[s],
+				})
+			}
+			for _, s := range td.unknown {
+				cases = append(cases, utils.TestCase{
+					ScriptName:  s,
+					ExpectedErr: fmt.Errorf("[ERR]: unknown function %s", s),
+				})
+			}
+			for i, tc := range cases {
+				i, tc := i, tc
+				t.Run(fmt.Sprintf("test #%d for script %s", i, tc.ScriptName), func(t *testing.T) {
+					t.Parallel()
+					out, err := utils.RunScript(path.Join(outDir, outBin), tc.ScriptName, tc.Args)
+					if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+						t.Fatal(err)
+					}
+				})
+			}
+		})
+	}
+}
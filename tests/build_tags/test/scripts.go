@@ -0,0 +1,7 @@
+package main
+
+import "fmt"
+
+func PlainScript() {
+	fmt.Print("plain")
+}
@@ -0,0 +1,10 @@
+//go:build foo
+// +build foo
+
+package main
+
+import "fmt"
+
+func FooScript() {
+	fmt.Print("foo")
+}
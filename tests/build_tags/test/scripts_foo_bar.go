@@ -0,0 +1,10 @@
+//go:build foo && bar
+// +build foo,bar
+
+package main
+
+import "fmt"
+
+func FooBarScript() {
+	fmt.Print("foo bar")
+}
@@ -0,0 +1,97 @@
+//+build integration_tests
+
+package completion
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestCompletion(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	fishScript := `# fish completion for test_bin
+complete -c test_bin -f
+complete -c test_bin -n "__fish_use_subcommand" -a "help Deploy Status Connect completion version"
+complete -c test_bin -n "__fish_use_subcommand" -l version
+complete -c test_bin -n "__fish_use_subcommand" -l config -r -F
+complete -c test_bin -n "__fish_seen_subcommand_from completion" -a "bash zsh fish"
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -a "help"
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -l env -s e -r -a "dev staging prod"
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -l logFile -s l -r -F
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -l verbose -s v
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -l dryRun -s d
+complete -c test_bin -n "__fish_seen_subcommand_from Deploy" -l no-dryRun
+complete -c test_bin -n "__fish_seen_subcommand_from Status" -a "help"
+complete -c test_bin -n "__fish_seen_subcommand_from Connect" -a "help"
+complete -c test_bin -n "__fish_seen_subcommand_from Connect" -l cluster -s c -r -a "(test_bin __complete Connect --cluster (commandline -ct) 2>/dev/null)"
+`
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "completion",
+			Args:        []string{"fish"},
+			ExpectedOut: fishScript,
+		},
+		{
+			ScriptName:  "completion",
+			Args:        []string{"tcsh"},
+			ExpectedErr: fmt.Errorf("[ERR]: unsupported shell tcsh, expected bash, zsh or fish"),
+		},
+		{
+			ScriptName:  "completion",
+			ExpectedErr: fmt.Errorf("[ERR]: expected a shell name: bash, zsh or fish"),
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "prod", "-vv"},
+			ExpectedOut: "env: prod, verbose: 2, dry run: false",
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "qa"},
+			ExpectedErr: fmt.Errorf("[ERR]: flag --env: unexpected value \"qa\", expected one of [dev, staging, prod]"),
+		},
+		{
+			ScriptName:  "__complete",
+			Args:        []string{"Connect", "-c", "eu"},
+			ExpectedOut: "eu-west\neu-north\n",
+		},
+		{
+			ScriptName:  "__complete",
+			Args:        []string{"Deploy", "--env", ""},
+			ExpectedOut: "",
+		},
+		{
+			ScriptName:  "Connect",
+			Args:        []string{"--cluster", "us-east"},
+			ExpectedOut: "connected to us-east",
+		},
+		{
+			ScriptName:  "CompleteClusters",
+			Args:        []string{"--prefix", "eu"},
+			ExpectedErr: fmt.Errorf("[ERR]: unknown function CompleteClusters"),
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
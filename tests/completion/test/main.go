@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+//gosif:enum env dev staging prod
+//gosif:count verbose
+func Deploy(env string, logFile *string, verbose int, dryRun bool) {
+	fmt.Printf("env: %s, verbose: %d, dry run: %t", env, verbose, dryRun)
+}
+
+func Status() {
+	fmt.Print("ok")
+}
+
+//gosif:complete cluster CompleteClusters
+func Connect(cluster string) {
+	fmt.Printf("connected to %s", cluster)
+}
+
+func CompleteClusters(prefix string) []string {
+	candidates := make([]string, 0)
+	for _, cluster := range []string{"eu-west", "eu-north", "us-east"} {
+		if strings.HasPrefix(cluster, prefix) {
+			candidates = append(candidates, cluster)
+		}
+	}
+	return candidates
+}
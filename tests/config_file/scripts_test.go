@@ -0,0 +1,86 @@
+//+build integration_tests
+
+package config_file
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestConfigFile(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []utils.TestCase{
+		{
+			Args:        []string{"--config", "test/config.json", "Deploy"},
+			ExpectedOut: "env: prod, replicas: 3, tags: [a b], dry run: true",
+		},
+		{
+			Args:        []string{"--config=test/config.json", "Deploy", "--replicas", "5", "--no-dryRun"},
+			ExpectedOut: "env: prod, replicas: 5, tags: [a b], dry run: false",
+		},
+		{
+			Args:        []string{"--config", "test/config.json", "Deploy", "--tags"},
+			Env:         []string{"APP_DEPLOY_ENV=dev"},
+			ExpectedOut: "env: dev, replicas: 3, tags: [], dry run: true",
+		},
+		{
+			Args:        []string{"--config", "test/config.json", "Greet"},
+			ExpectedOut: "Hello, world!",
+		},
+		{
+			Args:        []string{"--config", "test/config.ini", "Deploy"},
+			ExpectedOut: "env: staging, replicas: 2, tags: [x y], dry run: false",
+		},
+		{
+			Args:        []string{"--config", "test/config.ini", "Greet", "--name", "you"},
+			ExpectedOut: "Hello, you!",
+		},
+		{
+			Args:        []string{"--config", "test/misspelled.ini", "Deploy", "--env", "prod"},
+			ExpectedErr: fmt.Errorf("[ERR]: config file test/misspelled.ini: unknown section \"Deplyo\", expected a function name"),
+		},
+		{
+			Args:        []string{"--config", "test/unknown.json", "Greet", "--name", "you"},
+			ExpectedErr: fmt.Errorf("[ERR]: config file test/unknown.json: unknown key \"replica\""),
+		},
+		{
+			Args:        []string{"--config", "test/unknown.ini", "Deploy"},
+			ExpectedErr: fmt.Errorf("[ERR]: config file test/unknown.ini: unknown key \"Deploy.replica\""),
+		},
+		{
+			Args:        []string{"--config", "test/invalid.json", "Deploy"},
+			ExpectedErr: fmt.Errorf("[ERR]: cast failed: config file test/invalid.json, key \"Deploy.replicas\": failed to cast many to int: strconv.ParseInt: parsing \"many\": invalid syntax"),
+		},
+		{
+			Args:        []string{"--config", "test/missing.json", "Deploy"},
+			ExpectedErr: fmt.Errorf("[ERR]: failed to read the config file: open test/missing.json: no such file or directory"),
+		},
+		{
+			Args:        []string{"--config"},
+			ExpectedErr: fmt.Errorf("[ERR]: the option --config expects a file path"),
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+//gosif:env-prefix app
+
+package main
+
+import "fmt"
+
+func Deploy(env string, replicas int, tags []string, dryRun bool) {
+	fmt.Printf("env: %s, replicas: %d, tags: %v, dry run: %t", env, replicas, tags, dryRun)
+}
+
+func Greet(name string) {
+	fmt.Printf("Hello, %s!", name)
+}
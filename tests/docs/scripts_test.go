@@ -0,0 +1,78 @@
+//+build integration_tests
+
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/generator"
+)
+
+const srcDir = "test"
+
+func TestDocs(t *testing.T) {
+	cases := []struct {
+		format      string
+		expectedDir string
+	}{
+		{
+			format:      "man",
+			expectedDir: "expected/man",
+		},
+		{
+			format:      "markdown",
+			expectedDir: "expected/markdown",
+		},
+		{
+			format:      "json",
+			expectedDir: "expected/json",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+			outDir := t.TempDir()
+			opts := &generator.Options{
+				ProgName: "tool",
+			}
+			if err := generator.GenerateDocs(srcDir, outDir, tc.format, opts); err != nil {
+				t.Fatalf("failed to generate the documentation: %v", err)
+			}
+			if err := compareDirs(outDir, tc.expectedDir); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func compareDirs(actualDir string, expectedDir string) error {
+	expectedFiles, err := os.ReadDir(expectedDir)
+	if err != nil {
+		return err
+	}
+	actualFiles, err := os.ReadDir(actualDir)
+	if err != nil {
+		return err
+	}
+	if len(actualFiles) != len(expectedFiles) {
+		return fmt.Errorf("expected %d files, but %d were generated", len(expectedFiles), len(actualFiles))
+	}
+	for _, f := range expectedFiles {
+		expected, err := os.ReadFile(filepath.Join(expectedDir, f.Name()))
+		if err != nil {
+			return err
+		}
+		actual, err := os.ReadFile(filepath.Join(actualDir, f.Name()))
+		if err != nil {
+			return fmt.Errorf("the file %s was not generated: %v", f.Name(), err)
+		}
+		if string(actual) != string(expected) {
+			return fmt.Errorf("the file %s differs from the expected one:\n%s", f.Name(), actual)
+		}
+	}
+	return nil
+}
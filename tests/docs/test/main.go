@@ -0,0 +1,39 @@
+//gosif:env-prefix tool
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deploy deploys the service to the environment. It waits until all the
+// replicas are ready.
+//
+// The tags are attached to the release (e.g. v1.2-rc).
+//
+//gosif:enum env dev staging prod
+//gosif:count verbose
+//gosif:complete env CompleteEnvs
+//gosif:doc env the environment to deploy to
+//gosif:doc tags the release tags, separated with | in the changelog
+//gosif:example --env prod --replicas 3
+//gosif:example -e dev --dryRun -vv
+func Deploy(env string, replicas int, tags []string, dryRun bool, verbose int, note *string) {
+	fmt.Printf("env: %s, replicas: %d", env, replicas)
+}
+
+// CompleteEnvs is not documented, since it only completes the environments.
+func CompleteEnvs(prefix string) []string {
+	return []string{prefix}
+}
+
+//gosif:positional name
+func Greet(name string) {
+	fmt.Printf("Hello, %s!", strings.TrimSpace(name))
+}
+
+// Version prints the version.
+func Version() {
+	fmt.Print("v1")
+}
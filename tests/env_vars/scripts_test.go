@@ -0,0 +1,76 @@
+//+build integration_tests
+
+package env_vars
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestEnvVars(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "Deploy",
+			Env:         []string{"DEPLOY_TOOL_DEPLOY_ENV=prod", "DEPLOY_TOOL_DEPLOY_REPLICAS=3", "DEPLOY_TOOL_DEPLOY_TAGS=a,b", "DEPLOY_TOOL_DEPLOY_DRY_RUN=yes"},
+			ExpectedOut: "env: prod, replicas: 3, tags: [a b], dry run: true",
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "staging", "--tags", "c"},
+			Env:         []string{"DEPLOY_TOOL_DEPLOY_ENV=prod", "DEPLOY_TOOL_DEPLOY_REPLICAS=3", "DEPLOY_TOOL_DEPLOY_TAGS=a,b"},
+			ExpectedOut: "env: staging, replicas: 3, tags: [c], dry run: false",
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--replicas", "1", "--tags"},
+			Env:         []string{"DEPLOY_TOOL_DEPLOY_ENV=prod", "DEPLOY_TOOL_DEPLOY_DRY_RUN=on"},
+			ExpectedOut: "env: prod, replicas: 1, tags: [], dry run: true",
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "prod", "--tags"},
+			ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--replicas\" was not passed"),
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "prod", "--tags"},
+			Env:         []string{"DEPLOY_TOOL_DEPLOY_REPLICAS=many"},
+			ExpectedErr: fmt.Errorf("[ERR]: cast failed: failed to cast many to int: strconv.ParseInt: parsing \"many\": invalid syntax"),
+		},
+		{
+			ScriptName:  "Login",
+			Env:         []string{"API_TOKEN=secret", "DEPLOY_TOOL_LOGIN_USER=admin"},
+			ExpectedOut: "token: secret, user: admin",
+		},
+		{
+			ScriptName:  "Login",
+			Args:        []string{"help"},
+			ExpectedOut: "Function Login\n\tUsage: Login [flags] [-- <token> [<user>]]\n\tRequired options:\n\t\t -t / --token     string [env: API_TOKEN]\n" +
+				"\tAvailable options:\n\t\t -t / --token     string [env: API_TOKEN]\n\t\t -u / --user      *string [env: DEPLOY_TOOL_LOGIN_USER]\n",
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
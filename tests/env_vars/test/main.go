@@ -0,0 +1,18 @@
+//gosif:env-prefix deploy-tool
+
+package main
+
+import "fmt"
+
+func Deploy(env string, replicas int, tags []string, dryRun bool) {
+	fmt.Printf("env: %s, replicas: %d, tags: %v, dry run: %t", env, replicas, tags, dryRun)
+}
+
+//gosif:env token API_TOKEN
+func Login(token string, user *string) {
+	u := "nil"
+	if user != nil {
+		u = *user
+	}
+	fmt.Printf("token: %s, user: %s", token, u)
+}
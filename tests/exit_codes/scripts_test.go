@@ -0,0 +1,151 @@
+//+build integration_tests
+
+package exit_codes
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestExitCodes(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []struct {
+		tc           utils.TestCase
+		expectedCode int
+	}{
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Divide",
+				Args:        []string{"-a", "4", "-b", "2"},
+				ExpectedOut: "2",
+			},
+			expectedCode: 0,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName: "help",
+			},
+			expectedCode: 0,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Divide",
+				Args:        []string{"-a", "4", "-b", "0"},
+				ExpectedErr: fmt.Errorf("[ERR]: panic: runtime error: integer divide by zero (set GOSIF_TRACEBACK=1 to print the stack trace)"),
+			},
+			expectedCode: 1,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Panic",
+				ExpectedErr: fmt.Errorf("[ERR]: panic: boom (set GOSIF_TRACEBACK=1 to print the stack trace)"),
+			},
+			expectedCode: 1,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Check",
+				Args:        []string{"--path", "missing"},
+				ExpectedErr: fmt.Errorf("[ERR]: the path missing cannot be checked"),
+			},
+			expectedCode: 1,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Check",
+				Args:        []string{"--path", "."},
+				ExpectedOut: "ok",
+			},
+			expectedCode: 0,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Count",
+				ExpectedErr: fmt.Errorf("[ERR]: nothing to count"),
+			},
+			expectedCode: 1,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Divide",
+				Args:        []string{"-a", "4", "-b", "zero"},
+				ExpectedErr: fmt.Errorf("[ERR]: cast failed: failed to cast zero to int: strconv.ParseInt: parsing \"zero\": invalid syntax"),
+			},
+			expectedCode: 2,
+		},
+		{
+			tc: utils.TestCase{
+				ScriptName:  "Divde",
+				ExpectedErr: fmt.Errorf("[ERR]: unknown function Divde"),
+			},
+			expectedCode: 2,
+		},
+		{
+			tc: utils.TestCase{
+				ExpectedErr: fmt.Errorf("[ERR]: no function name passed"),
+			},
+			expectedCode: 2,
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, c := range cases {
+		i, c := i, c
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, code, err := utils.RunScriptWithExitCode(binPath, c.tc.ScriptName, c.tc.Args, c.tc.Env)
+			if len(c.tc.ExpectedOut) == 0 && c.tc.ExpectedErr == nil {
+				// only the exit code is checked for the help message
+				out = ""
+			}
+			if err := utils.CheckRunScriptResult(&c.tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+			if code != c.expectedCode {
+				t.Fatalf("expected the exit code %d, got %d", c.expectedCode, code)
+			}
+		})
+	}
+	t.Run("traceback", func(t *testing.T) {
+		t.Parallel()
+		_, code, err := utils.RunScriptWithExitCode(binPath, "Panic", nil, []string{"GOSIF_TRACEBACK=1"})
+		if err == nil || !strings.HasPrefix(err.Error(), "[ERR]: panic: boom\n\ngoroutine ") {
+			t.Fatalf("expected the stack trace of the panic, got \"%v\"", err)
+		}
+		if code != 1 {
+			t.Fatalf("expected the exit code 1, got %d", code)
+		}
+	})
+	t.Run("interrupt", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command(binPath, "Wait")
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(200 * time.Millisecond)
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			t.Fatal(err)
+		}
+		cmd.Wait()
+		status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if !ok || !status.Signaled() || status.Signal() != syscall.SIGINT {
+			t.Fatalf("expected the binary to be killed by SIGINT, got %v", cmd.ProcessState)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+func Divide(a int, b int) {
+	fmt.Print(a / b)
+}
+
+func Panic() {
+	panic("boom")
+}
+
+func Check(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("the path %s cannot be checked", path)
+	}
+	fmt.Print("ok")
+	return nil
+}
+
+func Count() (int, error) {
+	return 0, errors.New("nothing to count")
+}
+
+func Wait() {
+	time.Sleep(time.Minute)
+}
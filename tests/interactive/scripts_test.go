@@ -0,0 +1,117 @@
+//+build integration_tests
+
+package interactive
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+// TestInteractive checks that neither the menu nor the missing flags are
+// prompted for if the standard input is not a terminal, and that on a
+// terminal only the functions with the directive prompt without the option.
+func TestInteractive(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []utils.TestCase{
+		{
+			ExpectedErr: fmt.Errorf("[ERR]: no function name passed"),
+		},
+		{
+			ScriptName:  "--interactive",
+			ExpectedErr: fmt.Errorf("[ERR]: no function name passed"),
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "prod", "--replicas", "2"},
+			ExpectedOut: "env: prod, replicas: 2",
+		},
+		{
+			ScriptName:  "Deploy",
+			Args:        []string{"--env", "prod"},
+			ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--replicas\" was not passed"),
+		},
+		{
+			ScriptName:  "--interactive",
+			Args:        []string{"Greet"},
+			ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--name\" was not passed"),
+		},
+		{
+			ScriptName:  "--interactive",
+			Args:        []string{"Greet", "--name", "gosif"},
+			ExpectedOut: "Hello, gosif!",
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+	t.Run("menu help", func(t *testing.T) {
+		t.Parallel()
+		out, err := utils.RunScript(binPath, "help", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "To choose the function from a menu run the binary on a terminal without a function name") {
+			t.Fatalf("the help message does not advertise the menu: %q", out)
+		}
+	})
+	terminalCases := []struct {
+		scriptName        string
+		args              []string
+		input             string
+		expectedFirstLine string
+	}{
+		{
+			scriptName:        "Deploy",
+			args:              []string{"--env", "prod"},
+			input:             "2\n",
+			expectedFirstLine: "--replicas (int) the number of replicas: env: prod, replicas: 2",
+		},
+		{
+			input:             "2\ngosif\n",
+			expectedFirstLine: "Functions:",
+		},
+		{
+			scriptName:        "Greet",
+			input:             "gosif\n",
+			expectedFirstLine: "[ERR]: a required flag \"--name\" was not passed",
+		},
+	}
+	for i, tc := range terminalCases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("terminal test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, _, err := utils.RunScriptOnTerminal(binPath, tc.scriptName, tc.args, tc.input)
+			if errors.Is(err, utils.ErrNoTerminal) {
+				t.Skip(err)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if firstLine := strings.Split(out, "\n")[0]; firstLine != tc.expectedFirstLine {
+				t.Fatalf("expected output %q, but got %q", tc.expectedFirstLine, firstLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+//gosif:menu
+
+package main
+
+import "fmt"
+
+// Deploy prompts for the missing flags on a terminal.
+//
+//gosif:interactive
+//gosif:doc replicas the number of replicas
+func Deploy(env string, replicas int) {
+	fmt.Printf("env: %s, replicas: %d", env, replicas)
+}
+
+func Greet(name string) {
+	fmt.Printf("Hello, %s!", name)
+}
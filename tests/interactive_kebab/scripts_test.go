@@ -0,0 +1,78 @@
+//+build integration_tests
+
+package interactive_kebab
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+// TestInteractiveKebab checks that the multi-word flags of a kebab-case
+// package are prompted for on a terminal.
+func TestInteractiveKebab(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []struct {
+		args        []string
+		input       string
+		expectedOut string
+	}{
+		{
+			input:       "nightly\n3\n",
+			expectedOut: "--job-name (string): --max-retries (int): job: nightly, max retries: 3",
+		},
+		{
+			args:        []string{"--job-name", "nightly"},
+			input:       "many\n3\n",
+			expectedOut: "--max-retries (int): cast failed: failed to cast many to int: strconv.ParseInt: parsing \"many\": invalid syntax\n--max-retries (int): job: nightly, max retries: 3",
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	t.Run("no menu", func(t *testing.T) {
+		out, _, err := utils.RunScriptOnTerminal(binPath, "", nil, "1\n")
+		if errors.Is(err, utils.ErrNoTerminal) {
+			t.Skip(err)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(out, "[ERR]: no function name passed\n") {
+			t.Fatalf("expected the missing function error, got %q", out)
+		}
+		if strings.Contains(out, "menu") {
+			t.Fatalf("the help message advertises the menu: %q", out)
+		}
+	})
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, code, err := utils.RunScriptOnTerminal(binPath, "run-job", tc.args, tc.input)
+			if errors.Is(err, utils.ErrNoTerminal) {
+				t.Skip(err)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != 0 {
+				t.Fatalf("expected the exit code 0, got %d, output: %q", code, out)
+			}
+			if out != tc.expectedOut {
+				t.Fatalf("expected output %q, but got %q", tc.expectedOut, out)
+			}
+		})
+	}
+}
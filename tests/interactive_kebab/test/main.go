@@ -0,0 +1,12 @@
+//gosif:kebab-case
+
+package main
+
+import "fmt"
+
+// RunJob prompts for the missing flags on a terminal.
+//
+//gosif:interactive
+func RunJob(jobName string, maxRetries int) {
+	fmt.Printf("job: %s, max retries: %d", jobName, maxRetries)
+}
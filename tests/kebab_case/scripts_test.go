@@ -0,0 +1,116 @@
+//+build integration_tests
+
+package kebab_case
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestKebabCase(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--max-retries", "3", "--dry-run", "--server-url", "localhost"},
+			ExpectedOut: "maxRetries: 3, dryRun: true, serverURL: localhost",
+		},
+		{
+			ScriptName:  "PrintRetries",
+			Args:        []string{"--maxRetries", "3"},
+			ExpectedOut: "maxRetries: 3, dryRun: false, serverURL: nil",
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--max-retries", "3", "--dryRun", "--serverURL", "localhost"},
+			ExpectedOut: "maxRetries: 3, dryRun: true, serverURL: localhost",
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"-m", "3", "-d", "-s", "localhost"},
+			ExpectedOut: "maxRetries: 3, dryRun: true, serverURL: localhost",
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--max", "3", "--serv=localhost"},
+			ExpectedOut: "maxRetries: 3, dryRun: false, serverURL: localhost",
+		},
+		{
+			ScriptName:  "resize",
+			Args:        []string{"--max-w", "640", "--max-h", "480"},
+			ExpectedOut: "640x480",
+		},
+		{
+			ScriptName:  "resize",
+			Args:        []string{"--max", "640"},
+			ExpectedErr: fmt.Errorf("[ERR]: an ambiguous flag \"--max\" found, it may refer to --max-height, --max-width"),
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--max-retries", "3", "--no-dry-run"},
+			ExpectedOut: "maxRetries: 3, dryRun: false, serverURL: nil",
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--max-retries", "3", "--no-dryRun"},
+			ExpectedOut: "maxRetries: 3, dryRun: false, serverURL: nil",
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"--server_ulr", "localhost", "--max-retries", "3"},
+			ExpectedErr: fmt.Errorf("[ERR]: an unexpected flag \"--server_ulr\" found, did you mean --server-url?"),
+		},
+		{
+			ScriptName:  "ping",
+			ExpectedOut: "pong",
+		},
+		{
+			ScriptName:  "Ping",
+			ExpectedOut: "pong",
+		},
+		{
+			ScriptName:  "print-retries",
+			ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--max-retries\" was not passed"),
+		},
+		{
+			ScriptName:  "show-port",
+			ExpectedOut: "ShowPort",
+		},
+		{
+			ScriptName:  "Show_Port",
+			ExpectedErr: fmt.Errorf("[ERR]: unknown function Show_Port"),
+		},
+		{
+			ScriptName:  "connect",
+			ExpectedErr: fmt.Errorf("[ERR]: unknown function connect"),
+		},
+		{
+			ScriptName:  "print-retries",
+			Args:        []string{"help"},
+			ExpectedOut: "Function print-retries\n\tUsage: print-retries [flags] [-- <max-retries> [<server-url>]]\n\tRequired options:\n\t\t -m / --max-retriesint\n\tAvailable options:\n\t\t -m / --max-retriesint\n\t\t -d / --dry-run   bool\n\t\t -s / --server-url*string\n",
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScript(binPath, tc.ScriptName, tc.Args)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
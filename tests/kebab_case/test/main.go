@@ -0,0 +1,35 @@
+//gosif:kebab-case
+
+package main
+
+import "fmt"
+
+func PrintRetries(maxRetries int, dryRun bool, serverURL *string) {
+	url := "nil"
+	if serverURL != nil {
+		url = *serverURL
+	}
+	fmt.Printf("maxRetries: %d, dryRun: %t, serverURL: %s", maxRetries, dryRun, url)
+}
+
+func Resize(maxWidth int, maxHeight int) {
+	fmt.Printf("%dx%d", maxWidth, maxHeight)
+}
+
+func Ping() {
+	fmt.Print("pong")
+}
+
+func ShowPort() {
+	fmt.Print("ShowPort")
+}
+
+// Show_Port is skipped, since its command name clashes with ShowPort.
+func Show_Port() {
+	fmt.Print("Show_Port")
+}
+
+// Connect is skipped, since the flags of its parameters clash.
+func Connect(maxRetries int, max_retries int) {
+	fmt.Print(maxRetries, max_retries)
+}
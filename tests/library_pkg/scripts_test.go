@@ -0,0 +1,108 @@
+//+build integration_tests
+
+package library_pkg
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const pkgDir = "test/ops"
+const outDir = "test/cmd"
+const clashingPkgDir = "test/sort"
+const clashingOutDir = "test/sortcmd"
+const shadowedPkgDir = "test/flags"
+const shadowedOutDir = "test/flagscmd"
+
+func TestLibraryPackage(t *testing.T) {
+	if err := utils.SetupPackage(outBin, pkgDir, outDir); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		os.RemoveAll(outDir)
+	}
+	t.Cleanup(cleanup)
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "Greet",
+			Args:        []string{"--name", "gosif"},
+			ExpectedOut: "hello, gosif",
+		},
+		{
+			ScriptName:  "Greet",
+			Args:        []string{"--name", "gosif", "--loud"},
+			ExpectedOut: "HELLO, GOSIF",
+		},
+		{
+			ScriptName:  "Sum",
+			Args:        []string{"--nums", "1", "2", "3"},
+			ExpectedOut: "6",
+		},
+		{
+			ScriptName:  "Ping",
+			ExpectedOut: "pong",
+		},
+		{
+			ScriptName:  "helper",
+			ExpectedErr: fmt.Errorf("[ERR]: unknown function helper"),
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScript(binPath, tc.ScriptName, tc.Args)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// TestClashingLibraryPackage checks that a library package named as one of
+// the packages imported by the generated code is exposed.
+func TestClashingLibraryPackage(t *testing.T) {
+	if err := utils.SetupPackage(outBin, clashingPkgDir, clashingOutDir); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		os.RemoveAll(clashingOutDir)
+	}
+	t.Cleanup(cleanup)
+	tc := utils.TestCase{
+		ScriptName:  "Reverse",
+		Args:        []string{"--words", "a", "b", "c"},
+		ExpectedOut: "c b a",
+	}
+	out, err := utils.RunScript(path.Join(clashingOutDir, outBin), tc.ScriptName, tc.Args)
+	if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestShadowedLibraryPackage checks that a library package named as one of
+// the variables declared by the generated code is exposed.
+func TestShadowedLibraryPackage(t *testing.T) {
+	if err := utils.SetupPackage(outBin, shadowedPkgDir, shadowedOutDir); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		os.RemoveAll(shadowedOutDir)
+	}
+	t.Cleanup(cleanup)
+	tc := utils.TestCase{
+		ScriptName:  "Show",
+		Args:        []string{"--name", "gosif", "--verbose"},
+		ExpectedOut: "gosif, verbose true",
+	}
+	out, err := utils.RunScript(path.Join(shadowedOutDir, outBin), tc.ScriptName, tc.Args)
+	if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package flags has the name of a variable declared by the generated code.
+package flags
+
+import "fmt"
+
+func Show(name string, verbose bool) {
+	fmt.Printf("%s, verbose %t", name, verbose)
+}
@@ -0,0 +1,30 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Greet(name string, loud bool) {
+	greeting := fmt.Sprintf("hello, %s", name)
+	if loud {
+		greeting = strings.ToUpper(greeting)
+	}
+	fmt.Print(greeting)
+}
+
+func Sum(nums []int) {
+	var sum int
+	for _, n := range nums {
+		sum += n
+	}
+	fmt.Print(sum)
+}
+
+func Ping() {
+	fmt.Print("pong")
+}
+
+func helper(n int) {
+	fmt.Print(n)
+}
@@ -0,0 +1,15 @@
+// Package sort has the name of a package imported by the generated code.
+package sort
+
+import (
+	"fmt"
+	"strings"
+)
+
+func Reverse(words []string) {
+	reversed := make([]string, 0, len(words))
+	for i := len(words) - 1; i >= 0; i-- {
+		reversed = append(reversed, words[i])
+	}
+	fmt.Print(strings.Join(reversed, " "))
+}
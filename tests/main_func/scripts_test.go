@@ -89,6 +89,147 @@ func TestScripts(t *testing.T) {
 				Args:        []string{"-c64", "42", "-c128", "-42i"},
 				ExpectedOut: "c64: (42.000,0.000i), c128: (0.000,-42.000i)\nc64p: nil, c128p: nil",
 			},
+			{
+				ScriptName:  "IntSumScript",
+				Args:        []string{"--xs", "1", "2", "3", "--start", "10"},
+				ExpectedOut: "16",
+			},
+			{
+				ScriptName:  "FloatSumScript",
+				Args:        []string{"--xs", "0.5", "0.25"},
+				ExpectedOut: "0.75",
+			},
+			{
+				ScriptName:  "ComposedScript",
+				Args:        []string{"--s", "value", "--n", "42"},
+				ExpectedOut: "composed: value 42",
+			},
+			{
+				ScriptName:  "LiteralScript",
+				Args:        []string{"--s", "value"},
+				ExpectedOut: "literal: value",
+			},
+			{
+				ScriptName:  "TypedScript",
+				Args:        []string{"--s", "value"},
+				ExpectedOut: "typed: value",
+			},
+			{
+				ScriptName:  "NamedTypeScript",
+				Args:        []string{"--msg", "value"},
+				ExpectedOut: "named type: value",
+			},
+			{
+				ScriptName:  "PairScript",
+				Args:        []string{"--key", "answer", "--val", "42"},
+				ExpectedOut: "answer=42",
+			},
+			{
+				ScriptName:  "ClusterScript",
+				ExpectedOut: "all: false, verbose: 0, file: nil",
+			},
+			{
+				ScriptName:  "ClusterScript",
+				Args:        []string{"-vvv"},
+				ExpectedOut: "all: false, verbose: 3, file: nil",
+			},
+			{
+				ScriptName:  "ClusterScript",
+				Args:        []string{"-avvf", "out.tar", "--verbose"},
+				ExpectedOut: "all: true, verbose: 3, file: out.tar",
+			},
+			{
+				ScriptName:  "ClusterScript",
+				Args:        []string{"-vf=out.tar", "-a", "false"},
+				ExpectedOut: "all: false, verbose: 1, file: out.tar",
+			},
+			{
+				ScriptName:  "RepeatScript",
+				Args:        []string{"--tags", "a", "--level", "1", "--tags", "b", "c", "--tags=d,e", "--level", "2"},
+				ExpectedOut: "tags: [a b c d e], name: nil, level: 2",
+			},
+			{
+				ScriptName:  "RepeatScript",
+				Args:        []string{"--tags", "a\\,b", "c,d", "--level", "1"},
+				ExpectedOut: "tags: [a,b c d], name: nil, level: 1",
+			},
+			{
+				ScriptName:  "SepScript",
+				Args:        []string{"--ids", "1,-2,3", "--v", "1,2,3", "--paths", "/bin:/usr/bin", "--words", "a,b", "--cs", "(1,2i)"},
+				ExpectedOut: "ids: [1 -2 3], v: [1 2 3], paths: [\"/bin\" \"/usr/bin\"], words: [\"a,b\"], cs: [(1+2i)]",
+			},
+			{
+				ScriptName:  "SepScript",
+				Args:        []string{"--ids", "1,2", "--ids=3", "--v", "1,2", "3", "--paths", "a\\:b", "--words", "x", "--cs"},
+				ExpectedOut: "ids: [1 2 3], v: [1 2 3], paths: [\"a:b\"], words: [\"x\"], cs: []",
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"--arg=-not-a-flag"},
+				ExpectedOut: "-not-a-flag",
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"-arg=a=b"},
+				ExpectedOut: "a=b",
+			},
+			{
+				ScriptName:  "IntSumScript",
+				Args:        []string{"--xs=1,-2,3", "--start=-10"},
+				ExpectedOut: "-8",
+			},
+			{
+				ScriptName:  "CatScript",
+				Args:        []string{"--upper=false", "--files=a,b"},
+				ExpectedOut: "a b",
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"--", "-not-a-flag"},
+				ExpectedOut: "-not-a-flag",
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"prod", "v1.2.0"},
+				ExpectedOut: "deploy v1.2.0 to prod: replicas 1, dry run false",
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"prod", "--replicas", "3", "--dryRun", "--", "v1.2.0"},
+				ExpectedOut: "deploy v1.2.0 to prod: replicas 3, dry run true",
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"--version", "v1.2.0", "--env", "prod"},
+				ExpectedOut: "deploy v1.2.0 to prod: replicas 1, dry run false",
+			},
+			{
+				ScriptName:  "CatScript",
+				Args:        []string{"a", "b", "--upper", "--", "c", "--d"},
+				ExpectedOut: "A B C --D",
+			},
+			{
+				ScriptName: "DeployScript",
+				Args:       []string{"help"},
+				ExpectedOut: "Function DeployScript\n\tUsage: DeployScript <env> <version> [flags]\n\tRequired options:\n\t\t -e / --env       string\n\t\t -v / --version   string\n" +
+					"\tAvailable options:\n\t\t -e / --env       string\n\t\t -v / --version   string\n\t\t -r / --replicas  *int\n\t\t -d / --dryRun    bool\n",
+			},
+			{
+				ScriptName:  "ArchiveScript",
+				Args:        []string{"--force", "--level", "9", "--", "out.tar", "a", "true", "3"},
+				ExpectedOut: "out.tar: a true 3, force true, level 9",
+			},
+			{
+				ScriptName:  "ArchiveScript",
+				Args:        []string{"--files", "a", "--", "out.tar"},
+				ExpectedOut: "out.tar: a, force false, level 0",
+			},
+			{
+				ScriptName: "ArchiveScript",
+				Args:       []string{"help"},
+				ExpectedOut: "Function ArchiveScript\n\tUsage: ArchiveScript [flags] [-- <name> <files>...]\n\tRequired options:\n\t\t -n / --name      string\n\t\t -fi / --files     []string\n" +
+					"\tAvailable options:\n\t\t -n / --name      string\n\t\t -fo / --force     bool\n\t\t -fi / --files     []string\n\t\t -l / --level     *int\n",
+			},
 		}...)
 
 		errorCases := []utils.TestCase{
@@ -97,7 +238,7 @@ func TestScripts(t *testing.T) {
 			},
 			{
 				ScriptName:  "SimpleScript",
-				ExpectedErr: fmt.Errorf("[ERR]: a required flag \"-arg\" was not passed"),
+				ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--arg\" was not passed"),
 			},
 			{
 				ScriptName:  "SimpleScript",
@@ -112,7 +253,62 @@ func TestScripts(t *testing.T) {
 			{
 				ScriptName:  "SimpleScript",
 				Args:        []string{"--"},
-				ExpectedErr: fmt.Errorf("[ERR]: \"--\" found, but ending command options and passing positional arguments is NYI"),
+				ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--arg\" was not passed"),
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"--", "a", "b"},
+				ExpectedErr: fmt.Errorf("[ERR]: too many positional arguments: expected at most 1, got 2 ([a b])"),
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"--arg", "a", "--", "b"},
+				ExpectedErr: fmt.Errorf("[ERR]: the argument <arg> was passed both with the flag --arg and as the positional argument \"b\""),
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"prod"},
+				ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--version\" was not passed"),
+			},
+			{
+				ScriptName:  "ArchiveScript",
+				Args:        []string{"--", "out.tar"},
+				ExpectedErr: fmt.Errorf("[ERR]: a required flag \"--files\" was not passed"),
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"prod", "v1", "2", "3"},
+				ExpectedErr: fmt.Errorf("[ERR]: too many positional arguments: expected at most 2, got 4 ([prod v1 2 3])"),
+			},
+			{
+				ScriptName:  "DeployScript",
+				Args:        []string{"prod", "v1", "--replicas", "many"},
+				ExpectedErr: fmt.Errorf("[ERR]: cast failed: failed to cast many to int: strconv.ParseInt: parsing \"many\": invalid syntax"),
+			},
+			{
+				ScriptName:  "SimpleScript",
+				Args:        []string{"--n=123"},
+				ExpectedErr: fmt.Errorf("[ERR]: an unexpected flag \"--n\" found"),
+			},
+			{
+				ScriptName:  "RepeatScript",
+				Args:        []string{"--tags", "a", "--level", "1", "--name", "a", "--name", "b"},
+				ExpectedErr: fmt.Errorf("[ERR]: the flag --name was passed more than once (previously as --name)"),
+			},
+			{
+				ScriptName:  "ClusterScript",
+				Args:        []string{"-aa"},
+				ExpectedErr: fmt.Errorf("[ERR]: the flag -a was passed more than once (previously as -a)"),
+			},
+			{
+				ScriptName:  "SepScript",
+				Args:        []string{"--ids", "1", "--v", "1,2,3,4", "--paths", "a", "--words", "x", "--cs"},
+				ExpectedErr: fmt.Errorf("[ERR]: flag --v: expected 3 arguments, but got 4 ([1 2 3 4])"),
+			},
+			{
+				ScriptName:  "ClusterScript",
+				Args:        []string{"-fa", "out.tar"},
+				ExpectedErr: fmt.Errorf("[ERR]: an unexpected flag \"-fa\" found"),
 			},
 			{
 				ScriptName:  "SimpleScript",
@@ -137,12 +333,12 @@ func TestScripts(t *testing.T) {
 			{
 				ScriptName:  "IntScript",
 				Args:        []string{"-n", "123", "-n8", "-n16", "16", "-n32", "32", "-n64", "64"},
-				ExpectedErr: fmt.Errorf("[ERR]: could not get the argument passed to the flag \"-n8\": no arguments passed"),
+				ExpectedErr: fmt.Errorf("[ERR]: flag -n8: expected 1 argument, but got 0 ([])"),
 			},
 			{
 				ScriptName:  "IntScript",
 				Args:        []string{"-n", "123", "-n8", "8", "9", "-n16", "16", "-n32", "32", "-n64", "64"},
-				ExpectedErr: fmt.Errorf("[ERR]: could not get the argument passed to the flag \"-n8\": expected a single argument, got 2 ([8 9])"),
+				ExpectedErr: fmt.Errorf("[ERR]: flag -n8: expected 1 argument, but got 2 ([8 9])"),
 			},
 			// casting errors
 			{
@@ -151,7 +347,7 @@ func TestScripts(t *testing.T) {
 				ExpectedErr: fmt.Errorf("[ERR]: cast failed: failed to cast \"real\" to float32: strconv.ParseFloat: parsing \"real\": invalid syntax"),
 			},
 		}
-		unknownScripts := []string{"StructScript", "unexportedScript", "InexistentScript"}
+		unknownScripts := []string{"StructScript", "unexportedScript", "InexistentScript", "IgnoredScript", "TaggedScript", "TestSimpleScript", "GenericSum", "StringSumScript", "GenericNotInstantiated", "NotAFuncScript", "unexportedLiteralScript", "BadPositionalScript", "BadCounterScript"}
 		for _, s := range unknownScripts {
 			errorCases = append(errorCases, utils.TestCase{
 				ScriptName:  s,
@@ -172,8 +368,8 @@ func TestScripts(t *testing.T) {
 		}
 	})
 	t.Run("Test bool script", func(t *testing.T) {
-		trueBoolArgs := []string{"true", "t", "T", "TRUE", "tRUe"}
-		falseBoolArgs := []string{"false", "f", "FALSE", "F", "fAlSe"}
+		trueBoolArgs := []string{"true", "t", "T", "TRUE", "tRUe", "yes", "YES", "on", "On", "1"}
+		falseBoolArgs := []string{"false", "f", "FALSE", "F", "fAlSe", "no", "No", "off", "OFF", "0"}
 		boolTestCases := make([]utils.TestCase, 0)
 		generateOKBoolCasesFn := func(arg string, expectedBool bool) []utils.TestCase {
 			cases := make([]utils.TestCase, 0, 3*len(flagPrefixes))
@@ -228,6 +424,26 @@ func TestScripts(t *testing.T) {
 				Args:        []string{},
 				ExpectedOut: "b: false\nbp: nil",
 			},
+			{
+				ScriptName:  "BoolScript",
+				Args:        []string{"--no-b", "--no-bp"},
+				ExpectedOut: "b: false\nbp: false",
+			},
+			{
+				ScriptName:  "BoolScript",
+				Args:        []string{"-bp", "--no-b"},
+				ExpectedOut: "b: false\nbp: true",
+			},
+			{
+				ScriptName:  "BoolScript",
+				Args:        []string{"-b", "maybe"},
+				ExpectedErr: fmt.Errorf("[ERR]: cast failed: expected zero or one argument that must be any of [true, t, yes, on, 1, false, f, no, off, 0] (case insensitive), got: maybe"),
+			},
+			{
+				ScriptName:  "BoolScript",
+				Args:        []string{"-b", "--no-b"},
+				ExpectedErr: fmt.Errorf("[ERR]: the flag --no-b was passed more than once (previously as -b)"),
+			},
 		}...)
 		for i, tc := range boolTestCases {
 			i, tc := i, tc
@@ -307,7 +523,7 @@ func TestScripts(t *testing.T) {
 			{
 				ScriptName:  "StringArrScript",
 				Args:        []string{"--s", "1", "2", "3", "--sp", "1", "2", "3", "4"},
-				ExpectedErr: fmt.Errorf("[ERR]: flag --sp: expected 3 arguments, but got 4 ([1 2 3 4])"),
+				ExpectedErr: fmt.Errorf("[ERR]: flag --sp: expected at most 3 arguments, but got 4 ([1 2 3 4])"),
 			},
 			{
 				ScriptName:  "StringArrLengthOneScript",
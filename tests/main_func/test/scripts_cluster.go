@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+//gosif:count verbose
+func ClusterScript(all bool, verbose int, file *string) {
+	f := "nil"
+	if file != nil {
+		f = *file
+	}
+	fmt.Printf("all: %t, verbose: %d, file: %s", all, verbose, f)
+}
+
+//gosif:count name
+func BadCounterScript(name string) {
+	fmt.Print(name)
+}
@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+//gosif:instantiate GenericSum[int] as IntSumScript
+//gosif:instantiate GenericSum[float64] as FloatSumScript
+//gosif:instantiate GenericSum[string] as StringSumScript
+func GenericSum[T int | float64](xs []T, start *T) {
+	var sum T
+	if start != nil {
+		sum = *start
+	}
+	for _, x := range xs {
+		sum += x
+	}
+	fmt.Print(sum)
+}
+
+//gosif:instantiate GenericPair[string, int] as PairScript
+func GenericPair[K comparable, V any](key K, val V) {
+	fmt.Printf("%v=%v", key, val)
+}
+
+func GenericNotInstantiated[T any](val T) {
+	fmt.Print(val)
+}
@@ -0,0 +1,14 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "fmt"
+
+func SimpleScript(arg string) {
+	fmt.Print("this file must not be parsed")
+}
+
+func IgnoredScript(arg string) {
+	fmt.Print(arg)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+//gosif:positional env version
+func DeployScript(env string, version string, replicas *int, dryRun bool) {
+	n := 1
+	if replicas != nil {
+		n = *replicas
+	}
+	fmt.Printf("deploy %s to %s: replicas %d, dry run %t", version, env, n, dryRun)
+}
+
+//gosif:positional files
+func CatScript(files []string, upper bool) {
+	out := strings.Join(files, " ")
+	if upper {
+		out = strings.ToUpper(out)
+	}
+	fmt.Print(out)
+}
+
+//gosif:positional unknown
+func BadPositionalScript(arg string) {
+	fmt.Print(arg)
+}
+
+// ArchiveScript has no positional directive: only name and files are bound to
+// the arguments passed after "--", since force takes no value and files takes
+// all the remaining arguments.
+func ArchiveScript(name string, force bool, files []string, level *int) {
+	n := 0
+	if level != nil {
+		n = *level
+	}
+	fmt.Printf("%s: %s, force %t, level %d", name, strings.Join(files, " "), force, n)
+}
@@ -0,0 +1,12 @@
+package main
+
+import "fmt"
+
+//gosif:last-wins level
+func RepeatScript(tags []string, name *string, level int) {
+	n := "nil"
+	if name != nil {
+		n = *name
+	}
+	fmt.Printf("tags: %v, name: %s, level: %d", tags, n, level)
+}
@@ -0,0 +1,9 @@
+package main
+
+import "fmt"
+
+//gosif:sep paths :
+//gosif:sep words none
+func SepScript(ids []int, v [3]int, paths []string, words []string, cs []complex64) {
+	fmt.Printf("ids: %v, v: %v, paths: %q, words: %q, cs: %v", ids, v, paths, words, cs)
+}
@@ -0,0 +1,10 @@
+//go:build gosif_tagged
+// +build gosif_tagged
+
+package main
+
+import "fmt"
+
+func TaggedScript(arg string) {
+	fmt.Print(arg)
+}
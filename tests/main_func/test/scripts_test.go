@@ -0,0 +1,7 @@
+package main
+
+import "testing"
+
+func TestSimpleScript(t *testing.T) {
+	SimpleScript("")
+}
@@ -0,0 +1,31 @@
+package main
+
+import "fmt"
+
+type printerFunc func(msg string)
+
+var ComposedScript = withPrefix("composed")
+
+var LiteralScript = func(s string) {
+	fmt.Printf("literal: %s", s)
+}
+
+var TypedScript func(s string) = func(s string) {
+	fmt.Printf("typed: %s", s)
+}
+
+var NamedTypeScript printerFunc = func(msg string) {
+	fmt.Printf("named type: %s", msg)
+}
+
+var NotAFuncScript = "not a function"
+
+var unexportedLiteralScript = func(s string) {
+	fmt.Printf("unexported literal: %s", s)
+}
+
+func withPrefix(prefix string) func(s string, n int) {
+	return func(s string, n int) {
+		fmt.Printf("%s: %s %d", prefix, s, n)
+	}
+}
@@ -3,12 +3,16 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
+	"github.com/SergeyShpak/gosif/parser"
 	"github.com/SergeyShpak/gosif/tests/utils"
 )
 
@@ -87,3 +91,46 @@ func TestDirWithNoProcessabelFunctions(t *testing.T) {
 		t.Fatalf("failed to lookup the file %s: %v", mainPath, err)
 	}
 }
+
+// TestNestedPackages checks that the first main package in the lexical order
+// is picked and that the non-main packages in the subdirectories are not
+// parsed.
+func TestNestedPackages(t *testing.T) {
+	dir := "nested-pkgs.gen"
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("failed to remove %s: %v", dir, err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"cmd/a/main.go": "package main\n\nfunc A() {}\n",
+		"cmd/b/main.go": "package main\n\nfunc B() {}\n",
+		"lib/lib.go":    "package lib\n\nvar Handler = undefinedFunc()\n",
+	}
+	for name, content := range files {
+		filePath := path.Join(dir, name)
+		if err := os.MkdirAll(path.Dir(filePath), 0775); err != nil {
+			t.Fatalf("failed to create a directory %s: %v", path.Dir(filePath), err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("could not write the %s file: %v", filePath, err)
+		}
+	}
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	for i := 0; i < 10; i++ {
+		packages, err := parser.ParsePackagesFunctions(dir, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if packages.MainPackage == nil || packages.MainPackage.PackageDir != path.Join(dir, "cmd/a") {
+			t.Fatalf("expected the main package in %s, got %+v", path.Join(dir, "cmd/a"), packages.MainPackage)
+		}
+		if len(packages.OtherPacakges) != 0 {
+			t.Fatalf("expected no non-main packages, got %d", len(packages.OtherPacakges))
+		}
+	}
+	if strings.Contains(logs.String(), "Handler") {
+		t.Fatalf("the package lib was parsed: %s", logs.String())
+	}
+}
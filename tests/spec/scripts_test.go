@@ -0,0 +1,97 @@
+//+build integration_tests
+
+package spec
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestSpec(t *testing.T) {
+	if err := utils.Setup(outBin, outDir, true); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	spec := `{
+  "schemaVersion": 1,
+  "program": "test_bin",
+  "hasConfig": true,
+  "commands": [
+    {
+      "name": "Greet",
+      "function": "Greet",
+      "summary": "Greet greets the person",
+      "doc": "Greet greets the person.",
+      "usage": "test_bin Greet [flags] [-- <name> [<times>]]",
+      "flags": [
+        {
+          "name": "name",
+          "parameter": "name",
+          "shortName": "n",
+          "type": "string",
+          "optional": false,
+          "default": null,
+          "envVar": "",
+          "choices": [],
+          "description": "the name of the person"
+        },
+        {
+          "name": "times",
+          "parameter": "times",
+          "shortName": "t",
+          "type": "*int",
+          "optional": true,
+          "default": null,
+          "envVar": "",
+          "choices": [],
+          "description": ""
+        }
+      ],
+      "examples": [
+        "test_bin Greet --name gosif"
+      ]
+    },
+    {
+      "name": "Version",
+      "function": "Version",
+      "summary": "",
+      "doc": "",
+      "usage": "test_bin Version [flags]",
+      "flags": [],
+      "examples": []
+    }
+  ]
+}
+`
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "__spec",
+			ExpectedOut: spec,
+		},
+		{
+			ScriptName:  "Greet",
+			Args:        []string{"--name", "gosif"},
+			ExpectedOut: "Hello, gosif!",
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
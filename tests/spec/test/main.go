@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+// Greet greets the person.
+//
+//gosif:doc name the name of the person
+//gosif:example --name gosif
+func Greet(name string, times *int) {
+	fmt.Printf("Hello, %s!", name)
+}
+
+func Version() {
+	fmt.Print("v1")
+}
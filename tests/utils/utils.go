@@ -2,6 +2,8 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,13 +11,17 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/SergeyShpak/gosif/generator"
 )
 
 type TestCase struct {
-	ScriptName  string
-	Args        []string
+	ScriptName string
+	Args       []string
+	// Env are the additional environment variables of the script, in the
+	// "KEY=value" form
+	Env         []string
 	ExpectedOut string
 	ExpectedErr error
 }
@@ -35,7 +41,21 @@ func Setup(outBin string, outDir string, shouldRemoveArtifacts bool) error {
 	if shouldRemoveArtifacts {
 		RemoveArtifacts(outBin, outDir)
 	}
-	if err := generator.GenerateScriptsForDir(outDir); err != nil {
+	if err := generator.GenerateScriptsForDir(outDir, nil); err != nil {
+		return err
+	}
+	cmd := exec.Command("go", "build", "-o", outBin)
+	cmd.Dir = outDir
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func SetupPackage(outBin string, pkgDir string, outDir string) error {
+	RemoveArtifacts(outBin, outDir)
+	if err := generator.GenerateScriptsForPackage(pkgDir, outDir, nil); err != nil {
 		return err
 	}
 	cmd := exec.Command("go", "build", "-o", outBin)
@@ -48,12 +68,26 @@ func Setup(outBin string, outDir string, shouldRemoveArtifacts bool) error {
 }
 
 func RunScript(pathToBin string, scriptName string, args []string) (string, error) {
+	return RunScriptWithEnv(pathToBin, scriptName, args, nil)
+}
+
+func RunScriptWithEnv(pathToBin string, scriptName string, args []string, env []string) (string, error) {
+	out, _, err := RunScriptWithExitCode(pathToBin, scriptName, args, env)
+	return out, err
+}
+
+// RunScriptWithExitCode runs the script as RunScriptWithEnv does and returns
+// its exit code as well.
+func RunScriptWithExitCode(pathToBin string, scriptName string, args []string, env []string) (string, int, error) {
 	var cmd *exec.Cmd
 	if len(scriptName) != 0 {
 		cmd = exec.Command(pathToBin, append([]string{scriptName}, args...)...)
 	} else {
 		cmd = exec.Command(pathToBin, args...)
 	}
+	if len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	var stdoutBuffer, stderrBuf bytes.Buffer
 	cmd.Stdout = io.Writer(&stdoutBuffer)
 	cmd.Stderr = io.Writer(&stderrBuf)
@@ -64,7 +98,59 @@ func RunScript(pathToBin string, scriptName string, args []string) (string, erro
 	if len(cmdErrStr) != 0 {
 		errToReturn = fmt.Errorf(cmdErrStr)
 	}
-	return cmdOutStr, errToReturn
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	return cmdOutStr, exitCode, errToReturn
+}
+
+// ErrNoTerminal is returned by RunScriptOnTerminal if the script utility is
+// not installed.
+var ErrNoTerminal = errors.New("the script utility is required to run the binary on a terminal")
+
+// RunScriptOnTerminal runs the script on a pseudo-terminal created with the
+// script utility and types the input once the binary has started. The
+// terminal does not echo the input, stdout and stderr are merged in the
+// returned output and its "\r\n" line endings are replaced with "\n".
+func RunScriptOnTerminal(pathToBin string, scriptName string, args []string, input string) (string, int, error) {
+	scriptPath, err := exec.LookPath("script")
+	if err != nil {
+		return "", -1, ErrNoTerminal
+	}
+	words := []string{pathToBin}
+	if len(scriptName) != 0 {
+		words = append(words, scriptName)
+	}
+	words = append(words, args...)
+	for i, w := range words {
+		words[i] = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	command := "stty -echo; exec " + strings.Join(words, " ")
+	cmd := exec.CommandContext(ctx, scriptPath, "-qec", command, "/dev/null")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return "", -1, err
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Start(); err != nil {
+		return "", -1, err
+	}
+	time.Sleep(200 * time.Millisecond)
+	io.WriteString(stdin, input)
+	stdin.Close()
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return out.String(), -1, fmt.Errorf("the binary did not exit in time, output: %q", out.String())
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return "", -1, err
+	}
+	return strings.ReplaceAll(out.String(), "\r\n", "\n"), cmd.ProcessState.ExitCode(), nil
 }
 
 func CheckRunScriptResult(tc *TestCase, out string, err error) error {
@@ -0,0 +1,94 @@
+//go:build integration_tests
+// +build integration_tests
+
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/SergeyShpak/gosif/generator"
+	"github.com/SergeyShpak/gosif/tests/utils"
+)
+
+const outBin = "test_bin"
+const outDir = "test"
+
+func TestVersion(t *testing.T) {
+	utils.RemoveArtifacts(outBin, outDir)
+	cleanup := func() {
+		utils.RemoveArtifacts(outBin, outDir)
+	}
+	t.Cleanup(cleanup)
+	generator.Version = "v0.1.0"
+	if err := generator.GenerateScriptsForDir(outDir, nil); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	// the flags parsing runtime is left out, since no function takes
+	// parameters
+	mainGenGo, err := ioutil.ReadFile(path.Join(outDir, "main.gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(mainGenGo), "func gosif_ReadArgs(") {
+		t.Fatal("the generated file contains the flags parsing runtime")
+	}
+	cmd := exec.Command("go", "build", "-o", outBin, "-ldflags", "-X main.gosif_Version=v1.2.3 -X main.gosif_Revision=abc123 -X main.gosif_Modified=false")
+	cmd.Dir = outDir
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+	buildInfo := fmt.Sprintf(`module:   github.com/SergeyShpak/gosif
+version:  v1.2.3
+revision: abc123
+modified: false
+go:       %s
+gosif:    v0.1.0
+`, runtime.Version())
+	cases := []utils.TestCase{
+		{
+			ScriptName:  "--version",
+			ExpectedOut: buildInfo,
+		},
+		{
+			ScriptName:  "version",
+			ExpectedOut: "v1",
+		},
+		{
+			ScriptName:  "ping",
+			ExpectedOut: "pong",
+		},
+		{
+			// the version function is offered once, the build information
+			// with the --version option only
+			ScriptName: "completion",
+			Args:       []string{"fish"},
+			ExpectedOut: `# fish completion for test_bin
+complete -c test_bin -f
+complete -c test_bin -n "__fish_use_subcommand" -a "help version ping completion"
+complete -c test_bin -n "__fish_use_subcommand" -l version
+complete -c test_bin -n "__fish_seen_subcommand_from completion" -a "bash zsh fish"
+complete -c test_bin -n "__fish_seen_subcommand_from version Version" -a "help"
+complete -c test_bin -n "__fish_seen_subcommand_from ping Ping" -a "help"
+`,
+		},
+	}
+	binPath := path.Join(outDir, outBin)
+	for i, tc := range cases {
+		i, tc := i, tc
+		t.Run(fmt.Sprintf("test case #%d", i), func(t *testing.T) {
+			t.Parallel()
+			out, err := utils.RunScriptWithEnv(binPath, tc.ScriptName, tc.Args, tc.Env)
+			if err := utils.CheckRunScriptResult(&tc, out, err); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//gosif:kebab-case
+
+package main
+
+import "fmt"
+
+// Version clashes with the builtin version command, which is then available
+// as the --version option only.
+func Version() {
+	fmt.Print("v1")
+}
+
+func Ping() {
+	fmt.Print("pong")
+}